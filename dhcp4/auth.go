@@ -0,0 +1,322 @@
+package dhcp4
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	L "github.com/lsongdev/dhcp-go/dhcp4/options"
+)
+
+// Errors returned when verifying authenticated messages (RFC 3118).
+var (
+	ErrAuthenticationMissing = errors.New("dhcp4: authentication option missing")
+	ErrAuthenticationFailed  = errors.New("dhcp4: authentication failed")
+	ErrUnknownSecret         = errors.New("dhcp4: unknown authentication secret")
+	ErrReplayDetected        = errors.New("dhcp4: replayed message")
+)
+
+// AuthenticationError reports a received message that failed
+// authentication.
+type AuthenticationError struct {
+	Err error
+}
+
+func (e *AuthenticationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AuthenticationError) Unwrap() error {
+	return e.Err
+}
+
+// KeyStore looks up delayed authentication keys by secret ID.
+type KeyStore interface {
+	Key(secretID uint32) ([]byte, bool)
+}
+
+// MemoryKeyStore is a KeyStore held in memory. It is safe for
+// concurrent use.
+type MemoryKeyStore struct {
+	mu   sync.RWMutex
+	keys map[uint32][]byte
+}
+
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{keys: make(map[uint32][]byte)}
+}
+
+// Add stores key under secretID, replacing any previous key.
+func (s *MemoryKeyStore) Add(secretID uint32, key []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[secretID] = append([]byte(nil), key...)
+}
+
+// Remove deletes the key stored under secretID.
+func (s *MemoryKeyStore) Remove(secretID uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, secretID)
+}
+
+func (s *MemoryKeyStore) Key(secretID uint32) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[secretID]
+	return key, ok
+}
+
+// DefaultReplayHistory is how long an Authenticator remembers the
+// replay detection counter of a peer when ReplayHistory is not set.
+const DefaultReplayHistory = 24 * time.Hour
+
+// Authenticator signs and verifies messages with the delayed
+// authentication protocol of RFC 3118 using HMAC-MD5. Outgoing messages
+// are signed with the key for SecretID. Incoming messages must carry a
+// valid MAC for a key in the store and a replay detection counter
+// greater than the last one seen from the same peer.
+type Authenticator struct {
+	Keys     KeyStore
+	SecretID uint32
+	// ReplayHistory is how long the counter of a peer is remembered
+	// after its last verified message; DefaultReplayHistory when zero.
+	// Older messages of a forgotten peer can be replayed, so it should
+	// be longer than the lease time.
+	ReplayHistory time.Duration
+
+	counter atomic.Uint64
+	mu      sync.Mutex
+	seen    map[string]replayState
+	swept   time.Time
+}
+
+// replayState is the last replay detection counter verified for a peer.
+type replayState struct {
+	counter  uint64
+	verified time.Time
+}
+
+// NewAuthenticator returns an authenticator signing with the key stored
+// under secretID. Its replay detection counter starts at the current
+// time so that it keeps increasing across restarts.
+func NewAuthenticator(keys KeyStore, secretID uint32) *Authenticator {
+	a := &Authenticator{
+		Keys:     keys,
+		SecretID: secretID,
+		seen:     make(map[string]replayState),
+	}
+	a.counter.Store(uint64(time.Now().UnixNano()))
+	return a
+}
+
+func (a *Authenticator) nextReplayDetection() uint64 {
+	return a.counter.Add(1)
+}
+
+// RequestAuthentication adds the authentication option a client sends
+// in DHCPDISCOVER to ask the server for delayed authentication.
+func (a *Authenticator) RequestAuthentication(m *Message) {
+	m.SetOption(L.NewDelayedAuthenticationRequestOption(a.nextReplayDetection()))
+}
+
+// Sign adds a delayed authentication option to m and returns its wire
+// encoding with the MAC filled in.
+func (a *Authenticator) Sign(m *Message) ([]byte, error) {
+	a.prepare(m)
+	return a.sign(m.Bytes())
+}
+
+// prepare adds the authentication option with an all-zero MAC, so that
+// the message can be encoded before it is signed.
+func (a *Authenticator) prepare(m *Message) {
+	m.SetOption(L.NewDelayedAuthenticationOption(a.nextReplayDetection(), a.SecretID))
+}
+
+// sign fills in the MAC of the authentication option in data, which
+// must hold a message prepared with prepare.
+func (a *Authenticator) sign(data []byte) ([]byte, error) {
+	key, ok := a.Keys.Key(a.SecretID)
+	if !ok {
+		return nil, ErrUnknownSecret
+	}
+	offset, option, err := findAuthentication(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := option.SecretID(); !ok {
+		return nil, ErrAuthenticationMissing
+	}
+	offset += 4
+	copy(data[offset:], authenticationMAC(data, offset, key))
+	return data, nil
+}
+
+// Verify checks the authentication option of data, the wire encoding of
+// a received message. peer identifies the sender for replay detection.
+// A DHCPDISCOVER only has to ask for delayed authentication, as the
+// client cannot sign it before the server has chosen a key.
+func (a *Authenticator) Verify(data []byte, peer string) error {
+	offset, option, err := findAuthentication(data)
+	if err != nil {
+		return err
+	}
+	if !option.Delayed() {
+		return ErrAuthenticationFailed
+	}
+	secretID, ok := option.SecretID()
+	if !ok {
+		if len(option.Information) == 0 && messageType(data) == L.DHCPDISCOVER {
+			return nil
+		}
+		return ErrAuthenticationFailed
+	}
+	key, ok := a.Keys.Key(secretID)
+	if !ok {
+		return ErrUnknownSecret
+	}
+	mac := authenticationMAC(data, offset+4, key)
+	if !hmac.Equal(mac, option.MAC()) {
+		return ErrAuthenticationFailed
+	}
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.forgetPeers(now)
+	if last, ok := a.seen[peer]; ok && option.ReplayDetection <= last.counter {
+		return ErrReplayDetected
+	}
+	a.seen[peer] = replayState{counter: option.ReplayDetection, verified: now}
+	return nil
+}
+
+// forgetPeers drops the peers not verified within the replay history.
+// It sweeps at most once per history, so peers are kept for up to twice
+// as long.
+func (a *Authenticator) forgetPeers(now time.Time) {
+	history := a.ReplayHistory
+	if history <= 0 {
+		history = DefaultReplayHistory
+	}
+	if now.Sub(a.swept) < history {
+		return
+	}
+	for peer, state := range a.seen {
+		if now.Sub(state.verified) >= history {
+			delete(a.seen, peer)
+		}
+	}
+	a.swept = now
+}
+
+// replayPeer identifies the sender of request for replay detection. A
+// client is known by its client identifier. A leasequery carries the
+// identity of the client it asks about, so its requestor is known by
+// giaddr or, when that is not set, by the source address.
+func replayPeer(request *Message, source net.IP) string {
+	switch request.MessageType() {
+	case L.DHCPLEASEQUERY, L.DHCPBULKLEASEQUERY:
+		if request.GatewayIPAddr != nil && !request.GatewayIPAddr.Equal(net.IPv4zero) {
+			return "requestor:" + request.GatewayIPAddr.String()
+		}
+		return "requestor:" + source.String()
+	}
+	return request.ClientID()
+}
+
+// authenticationMAC computes the HMAC-MD5 of data with the hops and
+// giaddr fields and the MAC at offset set to zero (RFC 3118 section 2).
+func authenticationMAC(data []byte, offset int, key []byte) []byte {
+	input := append([]byte(nil), data...)
+	input[3] = 0
+	copy(input[24:28], make([]byte, 4))
+	copy(input[offset:offset+L.DelayedAuthenticationMACLength], make([]byte, L.DelayedAuthenticationMACLength))
+	h := hmac.New(md5.New, key)
+	h.Write(input)
+	return h.Sum(nil)
+}
+
+// findAuthentication returns the authentication option of data and the
+// offset of its authentication information. The option has to be a single instance, which may
+// sit in an overloaded file or sname field.
+func findAuthentication(data []byte) (int, L.AuthenticationOption, error) {
+	var option L.AuthenticationOption
+	fields, err := scanOptionFields(data)
+	if err != nil {
+		return 0, option, err
+	}
+	found := -1
+	for _, raw := range fields {
+		for _, r := range raw {
+			if r.code != L.OptionCodeAuthentication {
+				continue
+			}
+			if found >= 0 || int(data[r.offset+1]) != len(r.data) {
+				return 0, option, ErrAuthenticationFailed
+			}
+			decoded, err := option.Unmarshal(r.data)
+			if err != nil {
+				return 0, option, ErrAuthenticationFailed
+			}
+			option = decoded.(L.AuthenticationOption)
+			found = r.offset + 2 + 11
+		}
+	}
+	if found < 0 {
+		return 0, option, ErrAuthenticationMissing
+	}
+	return found, option, nil
+}
+
+// scanOptionFields scans the options field of data and the file and
+// sname fields it overloads, keeping each field's options apart.
+func scanOptionFields(data []byte) ([][]rawOption, error) {
+	if len(data) < optionsOffset {
+		return nil, &DecodeError{Offset: len(data), Err: ErrTruncated}
+	}
+	raw, err := scanOptions(nil, data, optionsOffset)
+	if err != nil {
+		return nil, err
+	}
+	fields := [][]rawOption{raw}
+	for _, r := range raw {
+		if r.code != L.OptionCodeOverload || len(r.data) != 1 {
+			continue
+		}
+		if r.data[0]&L.OverloadFile != 0 {
+			file, err := scanOptions(nil, data[:headerLength], fileOffset)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, file)
+		}
+		if r.data[0]&L.OverloadSName != 0 {
+			sname, err := scanOptions(nil, data[:fileOffset], snameOffset)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, sname)
+		}
+	}
+	return fields, nil
+}
+
+// messageType returns the DHCP message type of data, or 0.
+func messageType(data []byte) L.MessageType {
+	fields, err := scanOptionFields(data)
+	if err != nil {
+		return 0
+	}
+	for _, raw := range fields {
+		for _, r := range raw {
+			if r.code == L.OptionCodeMessageType && len(r.data) == 1 {
+				return L.MessageType(r.data[0])
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,200 @@
+package dhcp4
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lsongdev/dhcp-go/dhcp4/options"
+)
+
+func TestDelayedAuthentication(t *testing.T) {
+	keys := NewMemoryKeyStore()
+	keys.Add(7, []byte("management-vlan-secret"))
+	client := NewAuthenticator(keys, 7)
+	server := NewAuthenticator(keys, 7)
+
+	discover := NewDiscoverMessage()
+	client.RequestAuthentication(discover)
+	if err := server.Verify(discover.Bytes(), discover.ClientID()); err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+
+	request := NewRequestMessage()
+	request.SetOption(options.NewRequestedIPAddressOption("192.0.2.10"))
+	data, err := client.Sign(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Relays change hops and giaddr, which the MAC does not cover.
+	relayed := append([]byte(nil), data...)
+	relayed[3] = 1
+	copy(relayed[24:28], []byte{192, 0, 2, 1})
+	if err := server.Verify(relayed, request.ClientID()); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if err := server.Verify(data, request.ClientID()); !errors.Is(err, ErrReplayDetected) {
+		t.Fatalf("replay: err = %v, want ErrReplayDetected", err)
+	}
+
+	data, err = client.Sign(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := append([]byte(nil), data...)
+	tampered[16]++ // yiaddr
+	if err := server.Verify(tampered, request.ClientID()); !errors.Is(err, ErrAuthenticationFailed) {
+		t.Fatalf("tampered: err = %v, want ErrAuthenticationFailed", err)
+	}
+
+	if err := server.Verify(NewRequestMessage().Bytes(), "other"); !errors.Is(err, ErrAuthenticationMissing) {
+		t.Fatalf("unsigned: err = %v, want ErrAuthenticationMissing", err)
+	}
+	other := NewMemoryKeyStore()
+	other.Add(7, []byte("wrong"))
+	if err := NewAuthenticator(other, 7).Verify(data, request.ClientID()); !errors.Is(err, ErrAuthenticationFailed) {
+		t.Fatalf("wrong key: err = %v, want ErrAuthenticationFailed", err)
+	}
+}
+
+func TestAuthenticatorForgetsIdlePeers(t *testing.T) {
+	keys := NewMemoryKeyStore()
+	keys.Add(7, []byte("management-vlan-secret"))
+	client := NewAuthenticator(keys, 7)
+	server := NewAuthenticator(keys, 7)
+	server.ReplayHistory = time.Minute
+
+	verify := func(peer string) error {
+		t.Helper()
+		data, err := client.Sign(NewRequestMessage())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return server.Verify(data, peer)
+	}
+	for _, peer := range []string{"idle", "active"} {
+		if err := verify(peer); err != nil {
+			t.Fatalf("%s: %v", peer, err)
+		}
+	}
+	past := time.Now().Add(-2 * time.Minute)
+	server.seen["idle"] = replayState{counter: server.seen["idle"].counter, verified: past}
+	server.swept = past
+	if err := verify("active"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := server.seen["idle"]; ok || len(server.seen) != 1 {
+		t.Fatalf("peers = %v, want only the active one", server.seen)
+	}
+}
+
+func TestSignedReplySurvivesOverload(t *testing.T) {
+	keys := NewMemoryKeyStore()
+	keys.Add(1, []byte("secret"))
+	server := NewAuthenticator(keys, 1)
+	resp := NewOfferMessage(NewDiscoverMessage(), "192.0.2.10")
+	var routers []string
+	for i := 0; i < 70; i++ {
+		routers = append(routers, "192.0.2.1")
+	}
+	resp.SetOption(options.NewRouterOption(routers))
+	server.prepare(resp)
+	data, err := encodeReply(resp, defaultMaxMessageSize-ipUDPHeaderLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err = server.sign(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewAuthenticator(keys, 1).Verify(data, "server"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerDropsUnauthenticatedRequests(t *testing.T) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := NewMemoryKeyStore()
+	keys.Add(3, []byte("secret"))
+	handler := &testHandler{requests: make(chan *Message, 2)}
+	server := NewServer("", handler)
+	server.Authenticator = NewAuthenticator(keys, 3)
+	go server.Serve(conn)
+	defer server.Close()
+
+	client, err := net.DialUDP("udp4", nil, conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if _, err := client.Write(NewRequestMessage().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := NewAuthenticator(keys, 3).Sign(NewRequestMessage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Write(signed); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case req := <-handler.requests:
+		if req.GetOption(options.OptionCodeAuthentication) == nil {
+			t.Fatal("handler received unsigned request")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler did not receive request")
+	}
+	if server.UnauthenticatedCount() != 1 || server.RequestCount() != 1 {
+		t.Fatalf("unauthenticated = %d, requests = %d", server.UnauthenticatedCount(), server.RequestCount())
+	}
+}
+
+func TestLeaseQueryRequestorHasItsOwnReplayCounter(t *testing.T) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := NewMemoryKeyStore()
+	keys.Add(3, []byte("secret"))
+	handler := &testHandler{requests: make(chan *Message, 3)}
+	server := NewServer("", handler)
+	server.Authenticator = NewAuthenticator(keys, 3)
+	go server.Serve(conn)
+	defer server.Close()
+
+	sender, err := net.DialUDP("udp4", nil, conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sender.Close()
+	// The requestor's counter starts later and so is ahead of the
+	// client's.
+	client := NewAuthenticator(keys, 3)
+	requestor := NewAuthenticator(keys, 3)
+	send := func(a *Authenticator, m *Message) {
+		t.Helper()
+		data, err := a.Sign(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sender.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-handler.requests:
+		case <-time.After(time.Second):
+			t.Fatalf("%s dropped, unauthenticated = %d", m.MessageType(), server.UnauthenticatedCount())
+		}
+	}
+	request := NewRequestMessage()
+	request.SetMacAddress("00:11:22:33:44:55")
+	send(client, request)
+	query := NewLeaseQueryByMacMessage("00:11:22:33:44:55")
+	query.GatewayIPAddr = net.ParseIP("127.0.0.1")
+	send(requestor, query)
+	send(client, request)
+}
@@ -0,0 +1,538 @@
+package dhcp4
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	L "github.com/lsongdev/dhcp-go/dhcp4/options"
+)
+
+// maxFrameLength is the largest message a bulk leasequery connection
+// carries; each one is preceded by its length in two octets (RFC 6926
+// section 6.3).
+const maxFrameLength = 0xffff
+
+// Defaults for Server.BulkLeaseQueryTimeout and
+// Server.MaxBulkLeaseQueryConns.
+const (
+	DefaultBulkLeaseQueryTimeout  = 300 * time.Second
+	DefaultMaxBulkLeaseQueryConns = 10
+)
+
+// BulkLeaseStore is a LeaseStore that can list its leases for bulk
+// leasequery (RFC 6926).
+type BulkLeaseStore interface {
+	LeaseStore
+	// ActiveLeases returns every bound lease.
+	ActiveLeases() []Lease
+}
+
+// RelayAgentRecorder is implemented by lease stores that keep the relay
+// agent information of bound clients, so that bulk leasequery can find
+// leases by relay-id or remote-id. DefaultServerMux records it whenever
+// a DHCPACK is sent.
+type RelayAgentRecorder interface {
+	RecordRelayAgentInformation(ip net.IP, info L.RelayAgentInformationOption)
+}
+
+// LeaseQueryStatusError reports a bulk leasequery the server refused or
+// stopped before sending all leases.
+type LeaseQueryStatusError struct {
+	Status  L.StatusCode
+	Message string
+}
+
+func (e *LeaseQueryStatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("dhcp4: leasequery status %s", e.Status)
+	}
+	return fmt.Sprintf("dhcp4: leasequery status %s: %s", e.Status, e.Message)
+}
+
+// serveBulkLeaseQuery streams a DHCPLEASEACTIVE for every lease matching
+// req, followed by a DHCPLEASEQUERYDONE (RFC 6926 section 7.4).
+func (d *DefaultServerMux) serveBulkLeaseQuery(req *Message, rw ResponseWriter) {
+	store, ok := d.Leases.(BulkLeaseStore)
+	if !ok {
+		_ = rw.WriteResponse(newLeaseQueryStatusMessage(req, L.StatusNotAllowed, "bulk leasequery not supported"))
+		return
+	}
+	now := time.Now()
+	for _, lease := range bulkLeaseQueryMatches(req, store) {
+		if err := rw.WriteResponse(newLeaseActiveMessage(req, lease, now)); err != nil {
+			return
+		}
+	}
+	done := NewReplyMessage(req)
+	done.SetMessageType(L.DHCPLEASEQUERYDONE)
+	_ = rw.WriteResponse(done)
+}
+
+// bulkLeaseQueryMatches returns the leases asked for by req: the lease
+// of ciaddr, the leases of a client identifier, relay-id, remote-id or
+// hardware address, or every lease when the query names none of them.
+func bulkLeaseQueryMatches(req *Message, store BulkLeaseStore) []Lease {
+	if !req.ClientIPAddr.Equal(net.IPv4zero) {
+		if lease, ok := store.LeaseByIP(req.ClientIPAddr); ok {
+			return []Lease{lease}
+		}
+		return nil
+	}
+	if option := req.GetOption(L.OptionCodeClientIdentifier); option != nil {
+		return store.LeasesByClientID(option.Encode())
+	}
+	relay, _ := req.RelayAgentInformation()
+	if id := relay.RelayID(); id != nil {
+		return filterLeases(store.ActiveLeases(), func(lease Lease) bool {
+			return bytes.Equal(lease.RelayAgentInformation.RelayID(), id)
+		})
+	}
+	if id := relay.RemoteID(); id != nil {
+		return filterLeases(store.ActiveLeases(), func(lease Lease) bool {
+			return bytes.Equal(lease.RelayAgentInformation.RemoteID(), id)
+		})
+	}
+	if req.HardwareLength > 0 && !bytes.Equal(req.ClientHardwareAddr, make([]byte, len(req.ClientHardwareAddr))) {
+		return store.LeasesByHardwareAddr(req.ClientHardwareAddr[:req.HardwareLength])
+	}
+	return store.ActiveLeases()
+}
+
+func filterLeases(leases []Lease, match func(Lease) bool) []Lease {
+	var matched []Lease
+	for _, lease := range leases {
+		if match(lease) {
+			matched = append(matched, lease)
+		}
+	}
+	return matched
+}
+
+func newLeaseQueryStatusMessage(req *Message, status L.StatusCode, message string) *Message {
+	reply := NewReplyMessage(req)
+	reply.SetMessageType(L.DHCPLEASEQUERYSTATUS)
+	reply.SetOption(L.NewStatusCodeOption(status, message))
+	return reply
+}
+
+// recordRelayAgentInformation wraps rw so that the relay agent
+// information of req is stored with the lease a DHCPACK binds.
+func (d *DefaultServerMux) recordRelayAgentInformation(req *Message, rw ResponseWriter) ResponseWriter {
+	recorder, ok := d.Leases.(RelayAgentRecorder)
+	if !ok {
+		return rw
+	}
+	info, ok := req.RelayAgentInformation()
+	if !ok {
+		return rw
+	}
+	return &relayRecordingWriter{ResponseWriter: rw, request: req, recorder: recorder, info: info}
+}
+
+type relayRecordingWriter struct {
+	ResponseWriter
+	request  *Message
+	recorder RelayAgentRecorder
+	info     L.RelayAgentInformationOption
+}
+
+func (w *relayRecordingWriter) SendAck(ip string, responseOptions ...L.Option) {
+	_ = w.WriteResponse(NewAckMessage(w.request, ip), responseOptions...)
+}
+
+func (w *relayRecordingWriter) WriteResponse(resp *Message, responseOptions ...L.Option) error {
+	if err := w.ResponseWriter.WriteResponse(resp, responseOptions...); err != nil {
+		return err
+	}
+	if resp.MessageType() == L.DHCPACK && !resp.YourIPAddr.Equal(net.IPv4zero) {
+		w.recorder.RecordRelayAgentInformation(resp.YourIPAddr, w.info)
+	}
+	return nil
+}
+
+// ListenAndServeBulkLeaseQuery listens on TCP at s.Addr and serves bulk
+// leasequery connections. It runs alongside ListenAndServe.
+func (s *Server) ListenAndServeBulkLeaseQuery() error {
+	l, err := net.Listen("tcp4", s.Addr)
+	if err != nil {
+		return fmt.Errorf("dhcp4: listen tcp %s: %w", s.Addr, err)
+	}
+	return s.ServeBulkLeaseQuery(l)
+}
+
+// ServeBulkLeaseQuery accepts bulk leasequery connections (RFC 6926) on
+// l. Each DHCPBULKLEASEQUERY read from a connection is passed to the
+// Handler, whose replies are written back on the connection in order.
+// A connection is closed when it sends anything else, a query that
+// fails verification by the Authenticator, or nothing for
+// BulkLeaseQueryTimeout. Replies are signed by the
+// Authenticator when it is set.
+func (s *Server) ServeBulkLeaseQuery(l net.Listener) error {
+	if s.Handler == nil {
+		_ = l.Close()
+		return errors.New("dhcp4: nil handler")
+	}
+	s.mu.Lock()
+	if s.listener != nil {
+		s.mu.Unlock()
+		_ = l.Close()
+		return errors.New("dhcp4: server already serving bulk leasequery")
+	}
+	s.listener = l
+	s.bulkConns = make(map[net.Conn]struct{})
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		if s.listener == l {
+			s.listener = nil
+		}
+		for conn := range s.bulkConns {
+			_ = conn.Close()
+		}
+		s.bulkConns = nil
+		s.mu.Unlock()
+		_ = l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.RLock()
+			closed := s.closed
+			s.mu.RUnlock()
+			if closed || errors.Is(err, net.ErrClosed) {
+				return ErrServerClosed
+			}
+			return fmt.Errorf("dhcp4: accept bulk leasequery connection: %w", err)
+		}
+		s.mu.Lock()
+		if s.bulkConns == nil || len(s.bulkConns) >= s.maxBulkLeaseQueryConns() {
+			s.mu.Unlock()
+			_ = conn.Close()
+			continue
+		}
+		s.bulkConns[conn] = struct{}{}
+		s.mu.Unlock()
+		go s.serveBulkLeaseQueryConn(conn)
+	}
+}
+
+func (s *Server) serveBulkLeaseQueryConn(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.bulkConns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+	r := bufio.NewReader(conn)
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(s.bulkLeaseQueryTimeout())); err != nil {
+			return
+		}
+		data, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		request, err := FromBytes(data)
+		if err != nil {
+			s.malformed.Add(1)
+			return
+		}
+		if request.OpCode != OpCodeBootRequest || request.MessageType() != L.DHCPBULKLEASEQUERY {
+			return
+		}
+		if s.Authenticator != nil {
+			var source net.IP
+			if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+				source = addr.IP
+			}
+			if err := s.Authenticator.Verify(data, replayPeer(request, source)); err != nil {
+				s.unauthenticated.Add(1)
+				return
+			}
+		}
+		s.requests.Add(1)
+		s.Handler.ServeDHCP(request, &bulkResponseWriter{
+			conn:       conn,
+			request:    request,
+			alwaysSend: s.AlwaysSend,
+			filter:     !s.IgnoreParameterRequestList,
+			auth:       s.Authenticator,
+		})
+	}
+}
+
+func (s *Server) bulkLeaseQueryTimeout() time.Duration {
+	if s.BulkLeaseQueryTimeout <= 0 {
+		return DefaultBulkLeaseQueryTimeout
+	}
+	return s.BulkLeaseQueryTimeout
+}
+
+func (s *Server) maxBulkLeaseQueryConns() int {
+	if s.MaxBulkLeaseQueryConns <= 0 {
+		return DefaultMaxBulkLeaseQueryConns
+	}
+	return s.MaxBulkLeaseQueryConns
+}
+
+// bulkResponseWriter writes replies to a bulk leasequery on its TCP
+// connection.
+type bulkResponseWriter struct {
+	conn       net.Conn
+	request    *Message
+	alwaysSend []L.OptionCode
+	filter     bool
+	auth       *Authenticator
+}
+
+func (w *bulkResponseWriter) SendOffer(ip string, responseOptions ...L.Option) {
+	_ = w.WriteResponse(NewOfferMessage(w.request, ip), responseOptions...)
+}
+
+func (w *bulkResponseWriter) SendAck(ip string, responseOptions ...L.Option) {
+	_ = w.WriteResponse(NewAckMessage(w.request, ip), responseOptions...)
+}
+
+func (w *bulkResponseWriter) SendNak(reason string, responseOptions ...L.Option) {
+	_ = w.WriteResponse(NewNakMessage(w.request, reason), responseOptions...)
+}
+
+func (w *bulkResponseWriter) WriteResponse(resp *Message, responseOptions ...L.Option) error {
+	resp.OpCode = OpCodeBootReply
+	resp.Xid = w.request.Xid
+	applyResponseOptions(resp, responseOptions)
+	if w.filter {
+		filterResponseOptions(resp, w.request, w.alwaysSend)
+	}
+	if w.auth != nil {
+		w.auth.prepare(resp)
+	}
+	data, err := encodeReply(resp, maxFrameLength)
+	if err != nil {
+		return err
+	}
+	if w.auth != nil {
+		if data, err = w.auth.sign(data); err != nil {
+			return err
+		}
+	}
+	return writeFrame(w.conn, data)
+}
+
+// readFrame reads one length-prefixed message.
+func readFrame(r io.Reader) ([]byte, error) {
+	var length [2]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	data := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// writeFrame writes data preceded by its length.
+func writeFrame(w io.Writer, data []byte) error {
+	if len(data) > maxFrameLength {
+		return ErrMessageTooLarge
+	}
+	frame := make([]byte, 2+len(data))
+	binary.BigEndian.PutUint16(frame, uint16(len(data)))
+	copy(frame[2:], data)
+	_, err := w.Write(frame)
+	return err
+}
+
+// NewBulkLeaseQueryMessage queries every lease of the server. Change the
+// message type of a NewLeaseQuery message to DHCPBULKLEASEQUERY to query
+// by address, hardware address or client identifier instead.
+func NewBulkLeaseQueryMessage() *Message {
+	m := NewMessage()
+	m.OpCode = OpCodeBootRequest
+	m.HardwareType = 0
+	m.HardwareLength = 0
+	m.ClientHardwareAddr = nil
+	m.SetMessageType(L.DHCPBULKLEASEQUERY)
+	m.SetOption(L.NewParameterRequestOption([]L.OptionCode{
+		L.OptionCodeLeaseTime,
+		L.OptionCodeClientIdentifier,
+		L.OptionCodeRelayAgentInformation,
+		L.OptionCodeClientLastTransactionTime,
+	}))
+	return m
+}
+
+// NewBulkLeaseQueryByRelayIDMessage queries the leases of clients whose
+// requests were relayed by the relay agent with the given relay-id.
+func NewBulkLeaseQueryByRelayIDMessage(id []byte) *Message {
+	m := NewBulkLeaseQueryMessage()
+	m.SetOption(L.NewRelayAgentInformationOption(L.NewRelayIDSubOption(id)))
+	return m
+}
+
+// NewBulkLeaseQueryByRemoteIDMessage queries the leases of clients whose
+// requests carried the given relay agent remote-id.
+func NewBulkLeaseQueryByRemoteIDMessage(id []byte) *Message {
+	m := NewBulkLeaseQueryMessage()
+	m.SetOption(L.NewRelayAgentInformationOption(L.NewRemoteIDSubOption(id)))
+	return m
+}
+
+// LeaseIterator reads the DHCPLEASEACTIVE replies to a bulk leasequery:
+//
+//	for it.Next() {
+//		lease := it.Message()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+//
+// Close releases its connection.
+type LeaseIterator struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	xid     uint32
+	timeout time.Duration
+	// auth, when set, verifies every reply.
+	auth *Authenticator
+	msg  *Message
+	err  error
+	done bool
+}
+
+// BulkLeaseQuery sends query on conn, a TCP connection to the server's
+// bulk leasequery port, and returns an iterator over the replies. The
+// iterator takes ownership of conn.
+func BulkLeaseQuery(conn net.Conn, query *Message) (*LeaseIterator, error) {
+	return writeBulkLeaseQuery(conn, query.Bytes(), query.Xid)
+}
+
+func writeBulkLeaseQuery(conn net.Conn, data []byte, xid uint32) (*LeaseIterator, error) {
+	if err := writeFrame(conn, data); err != nil {
+		return nil, fmt.Errorf("failed to send bulk leasequery message: %s", err)
+	}
+	return &LeaseIterator{conn: conn, r: bufio.NewReader(conn), xid: xid}, nil
+}
+
+// BulkLeaseQuery connects to the server over TCP and sends query, built
+// with one of the NewBulkLeaseQuery functions. Each reply must arrive
+// within the client timeout. With an Authenticator, the query is signed
+// and the iteration stops at the first reply that fails verification.
+func (c *Client) BulkLeaseQuery(query *Message) (*LeaseIterator, error) {
+	conn, err := net.DialTimeout("tcp4", net.JoinHostPort(c.config.Server, "67"), c.config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	it, err := c.bulkLeaseQuery(conn, query)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return it, nil
+}
+
+// bulkLeaseQuery sends query on conn with the client's timeout and
+// authentication.
+func (c *Client) bulkLeaseQuery(conn net.Conn, query *Message) (*LeaseIterator, error) {
+	data, err := c.encode(query)
+	if err != nil {
+		return nil, err
+	}
+	it, err := writeBulkLeaseQuery(conn, data, query.Xid)
+	if err != nil {
+		return nil, err
+	}
+	it.timeout = c.config.Timeout
+	it.auth = c.config.Authenticator
+	return it, nil
+}
+
+// Next reads the next DHCPLEASEACTIVE. It returns false once the server
+// sent DHCPLEASEQUERYDONE or an error occurred.
+func (it *LeaseIterator) Next() bool {
+	it.msg = nil
+	for !it.done {
+		if it.timeout > 0 {
+			_ = it.conn.SetReadDeadline(time.Now().Add(it.timeout))
+		}
+		data, err := readFrame(it.r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			it.finish(err)
+			break
+		}
+		msg, err := FromBytes(data)
+		if err != nil {
+			it.finish(err)
+			break
+		}
+		if it.auth != nil {
+			serverID, _ := msg.GetOption(L.OptionCodeServerIdentifier).(L.ServerIdentifierOption)
+			if err := it.auth.Verify(data, serverID.ServerIdentifier.String()); err != nil {
+				it.finish(&AuthenticationError{Err: err})
+				break
+			}
+		}
+		if msg.Xid != it.xid {
+			continue
+		}
+		switch msg.MessageType() {
+		case L.DHCPLEASEACTIVE:
+			it.msg = msg
+			return true
+		case L.DHCPLEASEQUERYDONE, L.DHCPLEASEQUERYSTATUS:
+			it.finish(leaseQueryStatus(msg))
+		}
+	}
+	return false
+}
+
+func (it *LeaseIterator) finish(err error) {
+	it.done = true
+	it.err = err
+}
+
+// leaseQueryStatus returns the error reported by the status-code option
+// of a DHCPLEASEQUERYDONE or DHCPLEASEQUERYSTATUS message.
+func leaseQueryStatus(msg *Message) error {
+	option, ok := msg.GetOption(L.OptionCodeStatusCode).(L.StatusCodeOption)
+	if !ok {
+		if msg.MessageType() == L.DHCPLEASEQUERYSTATUS {
+			return &LeaseQueryStatusError{Status: L.StatusUnspecFail}
+		}
+		return nil
+	}
+	if option.Status == L.StatusSuccess {
+		return nil
+	}
+	return &LeaseQueryStatusError{Status: option.Status, Message: option.Message}
+}
+
+// Message returns the DHCPLEASEACTIVE read by the last call to Next.
+func (it *LeaseIterator) Message() *Message {
+	return it.msg
+}
+
+// Err returns the error that ended the iteration, or nil when the server
+// finished the query.
+func (it *LeaseIterator) Err() error {
+	return it.err
+}
+
+// Close closes the connection of the iterator.
+func (it *LeaseIterator) Close() error {
+	return it.conn.Close()
+}
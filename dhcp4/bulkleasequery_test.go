@@ -0,0 +1,267 @@
+package dhcp4
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lsongdev/dhcp-go/dhcp4/options"
+)
+
+type poolHandler struct {
+	ServerMuxHandler
+	pool *IPPool
+}
+
+func (h poolHandler) HandleRequest(request IGetRequestedIP, rw AckWriter) {
+	ip, err := h.pool.Allocate(request.GetMacAddress())
+	if err != nil {
+		return
+	}
+	rw.SendAck(ip.String())
+}
+
+func TestBulkLeaseQuery(t *testing.T) {
+	_, network, _ := net.ParseCIDR("192.0.2.0/24")
+	pool, err := NewIPPool(network, net.ParseIP("192.0.2.10"), net.ParseIP("192.0.2.20"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := NewDefaultServerMux(poolHandler{pool: pool})
+	mux.Leases = pool
+	relays := []options.RelayAgentInformationOption{
+		{SubOptions: []options.RelayAgentSubOption{options.NewRelayIDSubOption([]byte("relay-1")), options.NewRemoteIDSubOption([]byte("modem-1"))}},
+		{SubOptions: []options.RelayAgentSubOption{options.NewRelayIDSubOption([]byte("relay-1")), options.NewRemoteIDSubOption([]byte("modem-2"))}},
+		{SubOptions: []options.RelayAgentSubOption{options.NewRelayIDSubOption([]byte("relay-2"))}},
+	}
+	for i, relay := range relays {
+		req := NewRequestMessage()
+		req.SetMacAddress(net.HardwareAddr{0, 0x11, 0x22, 0x33, 0x44, byte(i)}.String())
+		req.SetOption(relay)
+		mux.ServeDHCP(req, &recordingWriter{request: req})
+	}
+
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer("", mux)
+	go server.ServeBulkLeaseQuery(l)
+	defer server.Close()
+
+	query := func(query *Message) ([]*Message, error) {
+		t.Helper()
+		conn, err := net.Dial("tcp4", l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		it, err := BulkLeaseQuery(conn, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer it.Close()
+		var leases []*Message
+		for it.Next() {
+			leases = append(leases, it.Message())
+		}
+		return leases, it.Err()
+	}
+
+	tests := []struct {
+		name  string
+		query *Message
+		want  int
+	}{
+		{"all", NewBulkLeaseQueryMessage(), 3},
+		{"relay-id", NewBulkLeaseQueryByRelayIDMessage([]byte("relay-1")), 2},
+		{"remote-id", NewBulkLeaseQueryByRemoteIDMessage([]byte("modem-2")), 1},
+		{"unknown relay-id", NewBulkLeaseQueryByRelayIDMessage([]byte("relay-9")), 0},
+	}
+	for _, tt := range tests {
+		leases, err := query(tt.query)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(leases) != tt.want {
+			t.Fatalf("%s: %d leases, want %d", tt.name, len(leases), tt.want)
+		}
+		for _, lease := range leases {
+			if lease.MessageType() != options.DHCPLEASEACTIVE || lease.ClientIPAddr.Equal(net.IPv4zero) {
+				t.Fatalf("%s: lease = %s", tt.name, lease)
+			}
+		}
+	}
+	leases, _ := query(NewBulkLeaseQueryByRemoteIDMessage([]byte("modem-2")))
+	if relay, _ := leases[0].RelayAgentInformation(); string(relay.RemoteID()) != "modem-2" || leases[0].GetMacAddress() != "00:11:22:33:44:01" {
+		t.Fatalf("lease = %s", leases[0])
+	}
+
+	mux.Leases = nil
+	_, err = query(NewBulkLeaseQueryMessage())
+	var statusErr *LeaseQueryStatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != options.StatusNotAllowed {
+		t.Fatalf("err = %v, want NotAllowed status", err)
+	}
+}
+
+func TestBulkLeaseQueryIsNotServedOverUDP(t *testing.T) {
+	_, network, _ := net.ParseCIDR("192.0.2.0/24")
+	pool, err := NewIPPool(network, net.ParseIP("192.0.2.10"), net.ParseIP("192.0.2.20"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Allocate("00:11:22:33:44:55"); err != nil {
+		t.Fatal(err)
+	}
+	mux := NewDefaultServerMux(poolHandler{pool: pool})
+	mux.Leases = pool
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer("", mux)
+	go server.Serve(conn)
+	defer server.Close()
+
+	client, err := net.DialUDP("udp4", nil, conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	for _, query := range []*Message{NewBulkLeaseQueryMessage(), NewLeaseQueryByIPMessage("192.0.2.10")} {
+		query.GatewayIPAddr = net.ParseIP("127.0.0.1")
+		if _, err := client.Write(query.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf := make([]byte, 1500)
+	_ = client.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := client.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := FromBytes(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.MessageType() != options.DHCPLEASEACTIVE {
+		t.Fatalf("reply = %s, want DHCPLEASEACTIVE", reply.MessageType())
+	}
+	if server.RequestCount() != 1 {
+		t.Fatalf("requests = %d, want only the leasequery", server.RequestCount())
+	}
+}
+
+func TestBulkLeaseQueryAuthentication(t *testing.T) {
+	_, network, _ := net.ParseCIDR("192.0.2.0/24")
+	pool, err := NewIPPool(network, net.ParseIP("192.0.2.10"), net.ParseIP("192.0.2.20"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Allocate("00:11:22:33:44:55"); err != nil {
+		t.Fatal(err)
+	}
+	mux := NewDefaultServerMux(poolHandler{pool: pool})
+	mux.Leases = pool
+	keys := NewMemoryKeyStore()
+	keys.Add(7, []byte("management-vlan-secret"))
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer("", mux)
+	server.Authenticator = NewAuthenticator(keys, 7)
+	go server.ServeBulkLeaseQuery(l)
+	defer server.Close()
+
+	dial := func() net.Conn {
+		t.Helper()
+		conn, err := net.Dial("tcp4", l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = conn.SetDeadline(time.Now().Add(time.Second))
+		return conn
+	}
+
+	conn := dial()
+	it, err := BulkLeaseQuery(conn, NewBulkLeaseQueryMessage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.Next() || it.Err() == nil {
+		t.Fatal("unauthenticated bulk leasequery was answered")
+	}
+	it.Close()
+	if server.UnauthenticatedCount() != 1 {
+		t.Fatalf("unauthenticated = %d, want 1", server.UnauthenticatedCount())
+	}
+
+	client := &Client{config: &ClientConfig{Timeout: time.Second, Authenticator: NewAuthenticator(keys, 7)}}
+	it, err = client.bulkLeaseQuery(dial(), NewBulkLeaseQueryMessage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for it.Next() {
+		n++
+	}
+	it.Close()
+	if err := it.Err(); err != nil || n != 1 {
+		t.Fatalf("leases = %d, err = %v", n, err)
+	}
+
+	// Replies signed with a key the client does not have stop the
+	// iteration.
+	keys.Add(8, []byte("other-secret"))
+	server.Authenticator.SecretID = 8
+	other := NewMemoryKeyStore()
+	other.Add(7, []byte("management-vlan-secret"))
+	client.config.Authenticator = NewAuthenticator(other, 7)
+	it, err = client.bulkLeaseQuery(dial(), NewBulkLeaseQueryMessage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	var authErr *AuthenticationError
+	if it.Next() || !errors.As(it.Err(), &authErr) {
+		t.Fatalf("err = %v, want AuthenticationError", it.Err())
+	}
+}
+
+func TestBulkLeaseQueryConnectionLimits(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer("", &testHandler{})
+	server.BulkLeaseQueryTimeout = 100 * time.Millisecond
+	server.MaxBulkLeaseQueryConns = 1
+	go server.ServeBulkLeaseQuery(l)
+	defer server.Close()
+
+	closed := func(conn net.Conn, within time.Duration) bool {
+		t.Helper()
+		_ = conn.SetReadDeadline(time.Now().Add(within))
+		_, err := conn.Read(make([]byte, 1))
+		var netErr net.Error
+		return err != nil && !(errors.As(err, &netErr) && netErr.Timeout())
+	}
+	idle, err := net.Dial("tcp4", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idle.Close()
+	extra, err := net.Dial("tcp4", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer extra.Close()
+	if !closed(extra, 50*time.Millisecond) {
+		t.Fatal("connection over the limit was served")
+	}
+	if !closed(idle, time.Second) {
+		t.Fatal("idle connection was not closed")
+	}
+}
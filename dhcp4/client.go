@@ -1,9 +1,12 @@
 package dhcp4
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/lsongdev/dhcp-go/dhcp4/options"
@@ -15,13 +18,51 @@ type ClientConfig struct {
 	ClientIP string
 	Hostname string
 	Timeout  time.Duration
+	// FQDN is sent in the client FQDN option (RFC 4702) when set to a
+	// valid domain name.
+	FQDN string
+	// FQDNServerUpdate asks the server to update the A record for FQDN.
+	FQDNServerUpdate bool
+	// RapidCommit offers the two message exchange of RFC 4039 in
+	// DHCPDISCOVER.
+	RapidCommit bool
+	// IPv6OnlyPreferred requests the IPv6-only preferred option
+	// (RFC 8925). Acquire then returns a *V6OnlyError instead of a lease
+	// when the server prefers the client to run IPv6-only.
+	IPv6OnlyPreferred bool
+	// Authenticator, when set, uses delayed authentication (RFC 3118):
+	// DHCPDISCOVER asks for it, other messages are signed and responses
+	// that fail verification are dropped.
+	Authenticator *Authenticator
+	// ForceRenew advertises forcerenew nonce authentication (RFC 6704)
+	// and keeps the nonce the server returns, so that WaitForceRenew can
+	// accept DHCPFORCERENEW messages.
+	ForceRenew bool
+}
+
+// V6OnlyError reports that the server asked the client to stop DHCPv4
+// for Wait and run IPv6-only.
+type V6OnlyError struct {
+	Wait time.Duration
+}
+
+func (e *V6OnlyError) Error() string {
+	return fmt.Sprintf("dhcp4: v6-only, retry DHCPv4 after %s", e.Wait)
 }
 
 // DHCP client behavior
 // https://datatracker.ietf.org/doc/html/rfc2131#section-4.4
 type Client struct {
-	conn   *net.UDPConn
-	config *ClientConfig
+	conn      *net.UDPConn
+	config    *ClientConfig
+	malformed atomic.Uint64
+	// unauthenticated counts responses dropped by the Authenticator.
+	unauthenticated atomic.Uint64
+
+	mu               sync.Mutex
+	nonce            []byte
+	forceRenewSeen   bool
+	forceRenewReplay uint64
 }
 
 func NewClient(config *ClientConfig) (c *Client, err error) {
@@ -41,18 +82,54 @@ func (c *Client) SetServer(addr string) {
 }
 
 func (c *Client) SendMessage(addr *net.UDPAddr, message *Message) (err error) {
-	_, err = c.conn.WriteTo(message.Bytes(), addr)
+	data, err := c.encode(message)
+	if err != nil {
+		return err
+	}
+	_, err = c.conn.WriteTo(data, addr)
 	return
 }
 
+// encode returns the wire encoding of message, signed when the client
+// uses authentication.
+func (c *Client) encode(message *Message) ([]byte, error) {
+	auth := c.config.Authenticator
+	if auth == nil {
+		return message.Bytes(), nil
+	}
+	if message.MessageType() == options.DHCPDISCOVER {
+		auth.RequestAuthentication(message)
+		return message.Bytes(), nil
+	}
+	return auth.Sign(message)
+}
+
 func (c *Client) Close() {
 	c.conn.Close()
 }
 
+// MalformedCount returns the number of responses dropped because they
+// could not be decoded.
+func (c *Client) MalformedCount() uint64 {
+	return c.malformed.Load()
+}
+
+// UnauthenticatedCount returns the number of responses dropped because
+// they failed authentication.
+func (c *Client) UnauthenticatedCount() uint64 {
+	return c.unauthenticated.Load()
+}
+
 // Receive returns a channel that receives DHCP response messages.
 // It reads from the connection continuously and sends valid messages to the channel.
 // The context can be used to cancel the receive process.
 func (c *Client) Receive() (msg *Message, err error) {
+	msg, _, err = c.receive()
+	return msg, err
+}
+
+// receive reads one response and also returns its wire encoding.
+func (c *Client) receive() (msg *Message, data []byte, err error) {
 	deadline := time.Now().Add(c.config.Timeout)
 	err = c.conn.SetReadDeadline(deadline)
 	if err != nil {
@@ -61,16 +138,25 @@ func (c *Client) Receive() (msg *Message, err error) {
 	respBuffer := make([]byte, 2048)
 	n, _, err := c.conn.ReadFrom(respBuffer)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read from connection: %s", err)
+		return nil, nil, fmt.Errorf("failed to read from connection: %s", err)
 	}
-	msg, err = FromBytes(respBuffer[:n])
+	data = respBuffer[:n]
+	msg, err = FromBytes(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse response message: %s", err)
+		c.malformed.Add(1)
+		return nil, nil, fmt.Errorf("failed to parse response message: %w", err)
 	}
 	if msg.OpCode != OpCodeBootReply {
-		return nil, fmt.Errorf("received message with invalid opcode: %d", msg.OpCode)
+		return nil, nil, fmt.Errorf("received message with invalid opcode: %d", msg.OpCode)
+	}
+	if auth := c.config.Authenticator; auth != nil {
+		serverID, _ := msg.GetOption(options.OptionCodeServerIdentifier).(options.ServerIdentifierOption)
+		if err := auth.Verify(data, serverID.ServerIdentifier.String()); err != nil {
+			c.unauthenticated.Add(1)
+			return nil, nil, &AuthenticationError{Err: err}
+		}
 	}
-	return msg, nil
+	return msg, data, nil
 }
 
 // ReceiveWithXid waits for a DHCP response message with the expected transaction ID.
@@ -78,6 +164,16 @@ func (c *Client) ReceiveWithXid(expectedXid uint32) (msg *Message, err error) {
 	log.Println("waiting for response (xid:", expectedXid, ")")
 	for {
 		received, err := c.Receive()
+		var decodeErr *DecodeError
+		if errors.As(err, &decodeErr) {
+			log.Println("dropped malformed response:", decodeErr)
+			continue
+		}
+		var authErr *AuthenticationError
+		if errors.As(err, &authErr) {
+			log.Println("dropped unauthenticated response:", authErr)
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -86,6 +182,7 @@ func (c *Client) ReceiveWithXid(expectedXid uint32) (msg *Message, err error) {
 			continue
 		}
 		log.Println("received matching response (xid:", received.Xid, ")")
+		c.storeForceRenewNonce(received)
 		return received, nil
 	}
 }
@@ -93,7 +190,13 @@ func (c *Client) ReceiveWithXid(expectedXid uint32) (msg *Message, err error) {
 func (c *Client) Discover() (offer *Message, err error) {
 	message := NewDiscoverMessage()
 	message.SetMacAddress(c.config.Mac)
-	message.SetOption(options.NewHostNameOption(c.config.Hostname))
+	message.SetHostName(c.config.Hostname)
+	c.setFQDN(message)
+	c.setIPv6OnlyPreferred(message)
+	c.setForceRenewNonceCapable(message)
+	if c.config.RapidCommit {
+		message.SetOption(options.NewRapidCommitOption())
+	}
 	// Set broadcast flag - server will broadcast response since client has no IP yet
 	message.Flags = 0x8000
 	// Save Xid to match response
@@ -105,12 +208,69 @@ func (c *Client) Discover() (offer *Message, err error) {
 	return c.ReceiveWithXid(expectedXid)
 }
 
+// Acquire obtains a lease. It returns the DHCPACK sent in answer to the
+// DHCPDISCOVER when the server accepted rapid commit, and otherwise
+// requests the offered address. It returns a *V6OnlyError when the
+// server prefers the client to run IPv6-only.
+func (c *Client) Acquire() (ack *Message, err error) {
+	offer, err := c.Discover()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkV6Only(offer); err != nil {
+		return nil, err
+	}
+	if offer.MessageType() == options.DHCPACK {
+		if !c.config.RapidCommit || !offer.RapidCommit() {
+			return nil, fmt.Errorf("received unexpected ack without rapid commit")
+		}
+		return offer, nil
+	}
+	ack, err = c.Request(offer)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkV6Only(ack); err != nil {
+		return nil, err
+	}
+	return ack, nil
+}
+
+// setForceRenewNonceCapable advertises forcerenew nonce authentication.
+func (c *Client) setForceRenewNonceCapable(message *Message) {
+	if c.config.ForceRenew {
+		message.SetOption(options.NewForcerenewNonceCapableOption(options.AuthenticationAlgorithmHMACMD5))
+	}
+}
+
+func (c *Client) setIPv6OnlyPreferred(message *Message) {
+	if c.config.IPv6OnlyPreferred {
+		message.RequestOption(options.OptionCodeIPv6OnlyPreferred)
+	}
+}
+
+// checkV6Only returns a *V6OnlyError when the client asked for the
+// IPv6-only preferred option and the server sent it (RFC 8925 section 3.2).
+func (c *Client) checkV6Only(resp *Message) error {
+	if !c.config.IPv6OnlyPreferred {
+		return nil
+	}
+	option, ok := resp.IPv6OnlyPreferred()
+	if !ok {
+		return nil
+	}
+	return &V6OnlyError{Wait: time.Duration(option.Wait()) * time.Second}
+}
+
 func (c *Client) Request(offer *Message) (ack *Message, err error) {
 	request := NewRequestMessage()
 	ip := offer.YourIPAddr.String()
 	request.SetMacAddress(c.config.Mac)
 	request.SetOption(options.NewRequestedIPAddressOption(ip))
 	request.SetOption(options.NewServerIdentifierOption(offer.ServerIPAddr.String()))
+	c.setFQDN(request)
+	c.setIPv6OnlyPreferred(request)
+	c.setForceRenewNonceCapable(request)
 	// Set broadcast flag - server will broadcast response since client has no IP yet
 	request.Flags = 0x8000
 	// Save Xid to match response
@@ -122,6 +282,17 @@ func (c *Client) Request(offer *Message) (ack *Message, err error) {
 	return c.ReceiveWithXid(expectedXid)
 }
 
+func (c *Client) setFQDN(message *Message) {
+	if c.config.FQDN == "" {
+		return
+	}
+	option := options.NewClientFQDNOption(c.config.FQDN, c.config.FQDNServerUpdate)
+	if option.(options.ClientFQDNOption).Validate() != nil {
+		return
+	}
+	message.SetOption(option)
+}
+
 func (c *Client) Decline(offer *Message, reason string) (ack *Message, err error) {
 	request := NewRequestMessage()
 	request.Xid = offer.Xid
@@ -147,6 +318,7 @@ func (c *Client) Renew() (ack *Message, err error) {
 	}
 	request := NewRenewMessage(c.config.ClientIP)
 	request.SetMacAddress(c.config.Mac)
+	c.setForceRenewNonceCapable(request)
 	// Save Xid to match response
 	expectedXid := request.Xid
 	serverAddr := net.UDPAddr{IP: net.ParseIP(c.config.Server), Port: 67}
@@ -0,0 +1,225 @@
+package dhcp4
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	L "github.com/lsongdev/dhcp-go/dhcp4/options"
+)
+
+// Errors returned by Server.ForceRenew.
+var (
+	ErrNoForceRenewNonce = errors.New("dhcp4: no forcerenew nonce for client")
+	ErrServerNotServing  = errors.New("dhcp4: server not serving")
+)
+
+// forceRenewLease is what the server needs to send an authenticated
+// DHCPFORCERENEW to a bound client (RFC 6704).
+type forceRenewLease struct {
+	clientID       string
+	hardwareType   uint8
+	hardwareLength uint8
+	chaddr         net.HardwareAddr
+	serverID       net.IP
+	nonce          []byte
+}
+
+// forceRenewLeases tracks the nonces handed out in DHCPACKs by the
+// address they were bound to.
+type forceRenewLeases struct {
+	mu      sync.Mutex
+	byIP    map[string]*forceRenewLease
+	counter atomic.Uint64
+}
+
+func newForceRenewLeases() *forceRenewLeases {
+	l := &forceRenewLeases{byIP: make(map[string]*forceRenewLease)}
+	l.counter.Store(uint64(time.Now().UnixNano()))
+	return l
+}
+
+// supportsForceRenewNonce reports whether req advertised forcerenew
+// nonce authentication with HMAC-MD5.
+func supportsForceRenewNonce(req *Message) bool {
+	option, ok := req.GetOption(L.OptionCodeForcerenewNonceCapable).(L.ForcerenewNonceCapableOption)
+	return ok && option.Supports(L.AuthenticationAlgorithmHMACMD5)
+}
+
+// bind records the lease acknowledged by ack and returns the option that
+// gives the client its nonce. A client keeps its nonce while it stays
+// on the same address.
+func (l *forceRenewLeases) bind(req, ack *Message) (L.Option, error) {
+	ip := ack.YourIPAddr.String()
+	clientID := req.ClientID()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lease, ok := l.byIP[ip]
+	if !ok || lease.clientID != clientID {
+		nonce := make([]byte, L.DelayedAuthenticationMACLength)
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		l.forget(clientID)
+		lease = &forceRenewLease{clientID: clientID, nonce: nonce}
+		l.byIP[ip] = lease
+	}
+	lease.hardwareType = req.HardwareType
+	lease.hardwareLength = req.HardwareLength
+	lease.chaddr = req.ClientHardwareAddr
+	if serverID, ok := ack.GetOption(L.OptionCodeServerIdentifier).(L.ServerIdentifierOption); ok {
+		lease.serverID = serverID.ServerIdentifier
+	}
+	return L.NewForcerenewNonceOption(l.counter.Add(1), lease.nonce), nil
+}
+
+// release drops the nonce of a client that released or declined its
+// address.
+func (l *forceRenewLeases) release(req *Message) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.forget(req.ClientID())
+}
+
+func (l *forceRenewLeases) forget(clientID string) {
+	for ip, lease := range l.byIP {
+		if lease.clientID == clientID {
+			delete(l.byIP, ip)
+		}
+	}
+}
+
+// message builds the signed DHCPFORCERENEW for the client bound to ip.
+func (l *forceRenewLeases) message(ip string) ([]byte, error) {
+	l.mu.Lock()
+	lease, ok := l.byIP[ip]
+	if !ok {
+		l.mu.Unlock()
+		return nil, ErrNoForceRenewNonce
+	}
+	// bind updates the lease in place.
+	snapshot := *lease
+	l.mu.Unlock()
+	lease = &snapshot
+	m := NewMessage()
+	m.OpCode = OpCodeBootReply
+	m.HardwareType = lease.hardwareType
+	m.HardwareLength = lease.hardwareLength
+	m.ClientHardwareAddr = lease.chaddr
+	m.ClientIPAddr = net.ParseIP(ip)
+	m.SetMessageType(L.DHCPFORCERENEW)
+	if lease.serverID != nil {
+		m.SetOption(L.NewServerIdentifierOption(lease.serverID.String()))
+	}
+	m.SetOption(L.NewForcerenewDigestOption(l.counter.Add(1)))
+	data := m.Bytes()
+	offset, _, err := findAuthentication(data)
+	if err != nil {
+		return nil, err
+	}
+	offset++
+	copy(data[offset:], authenticationMAC(data, offset, lease.nonce))
+	return data, nil
+}
+
+// ForceRenew sends a DHCPFORCERENEW to the client bound to ip, asking it
+// to renew its lease now (RFC 3203). The message is authenticated with
+// the nonce given to the client in its last DHCPACK, so ForceRenewNonce
+// must be enabled and the client must support it.
+func (s *Server) ForceRenew(ip string) error {
+	s.mu.RLock()
+	conn := s.conn
+	leases := s.forceRenew
+	s.mu.RUnlock()
+	if conn == nil {
+		return ErrServerNotServing
+	}
+	if leases == nil {
+		return ErrNoForceRenewNonce
+	}
+	data, err := leases.message(ip)
+	if err != nil {
+		return err
+	}
+	addr := &net.UDPAddr{IP: net.ParseIP(ip), Port: s.clientPort()}
+	if _, err := conn.WriteTo(data, addr); err != nil {
+		return fmt.Errorf("dhcp4: send forcerenew: %w", err)
+	}
+	return nil
+}
+
+// storeForceRenewNonce keeps the nonce sent in a DHCPACK for
+// authenticating later DHCPFORCERENEW messages.
+func (c *Client) storeForceRenewNonce(ack *Message) {
+	if !c.config.ForceRenew || ack.MessageType() != L.DHCPACK {
+		return
+	}
+	option, ok := ack.GetOption(L.OptionCodeAuthentication).(L.AuthenticationOption)
+	if !ok {
+		return
+	}
+	nonce, ok := option.ForcerenewNonce()
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nonce = append([]byte(nil), nonce...)
+}
+
+// verifyForceRenew checks the nonce authentication of a received
+// DHCPFORCERENEW and its replay detection counter.
+func (c *Client) verifyForceRenew(data []byte) error {
+	offset, option, err := findAuthentication(data)
+	if err != nil {
+		return err
+	}
+	digest, ok := option.ForcerenewDigest()
+	if !ok {
+		return ErrAuthenticationFailed
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.nonce == nil {
+		return ErrNoForceRenewNonce
+	}
+	if !hmac.Equal(authenticationMAC(data, offset+1, c.nonce), digest) {
+		return ErrAuthenticationFailed
+	}
+	if c.forceRenewSeen && option.ReplayDetection <= c.forceRenewReplay {
+		return ErrReplayDetected
+	}
+	c.forceRenewSeen = true
+	c.forceRenewReplay = option.ReplayDetection
+	return nil
+}
+
+// WaitForceRenew waits up to the client timeout for an authenticated
+// DHCPFORCERENEW and then renews the lease of ClientIP. Messages that
+// are not DHCPFORCERENEW or fail authentication are dropped.
+func (c *Client) WaitForceRenew() (ack *Message, err error) {
+	for {
+		msg, data, err := c.receive()
+		var decodeErr *DecodeError
+		var authErr *AuthenticationError
+		if errors.As(err, &decodeErr) || errors.As(err, &authErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if msg.MessageType() != L.DHCPFORCERENEW {
+			continue
+		}
+		if err := c.verifyForceRenew(data); err != nil {
+			c.unauthenticated.Add(1)
+			continue
+		}
+		return c.Renew()
+	}
+}
@@ -0,0 +1,107 @@
+package dhcp4
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lsongdev/dhcp-go/dhcp4/options"
+)
+
+type ackHandler struct{}
+
+func (ackHandler) ServeDHCP(req *Message, rw ResponseWriter) {
+	rw.SendAck("127.0.0.1", options.NewServerIdentifierOption("127.0.0.1"))
+}
+
+func TestForceRenewWithNonce(t *testing.T) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientConn.Close()
+	server := NewServer("", ackHandler{})
+	server.ClientPort = clientConn.LocalAddr().(*net.UDPAddr).Port
+	server.ForceRenewNonce = true
+	go server.Serve(conn)
+	defer server.Close()
+
+	read := func() []byte {
+		t.Helper()
+		buf := make([]byte, 1500)
+		clientConn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := clientConn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf[:n]
+	}
+
+	client := &Client{config: &ClientConfig{ForceRenew: true}}
+	request := NewRequestMessage()
+	request.SetMacAddress("00:11:22:33:44:55")
+	client.setForceRenewNonceCapable(request)
+	if _, err := clientConn.WriteTo(request.Bytes(), conn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	ack, err := FromBytes(read())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.storeForceRenewNonce(ack)
+	if client.nonce == nil {
+		t.Fatalf("ack carries no nonce: %s", ack)
+	}
+
+	if err := server.ForceRenew("127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	data := read()
+	forceRenew, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forceRenew.MessageType() != options.DHCPFORCERENEW || forceRenew.GetMacAddress() != "00:11:22:33:44:55" {
+		t.Fatalf("forcerenew = %s", forceRenew)
+	}
+	if err := client.verifyForceRenew(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.verifyForceRenew(data); !errors.Is(err, ErrReplayDetected) {
+		t.Fatalf("replay: err = %v, want ErrReplayDetected", err)
+	}
+	if err := server.ForceRenew("192.0.2.99"); !errors.Is(err, ErrNoForceRenewNonce) {
+		t.Fatalf("unknown client: err = %v, want ErrNoForceRenewNonce", err)
+	}
+}
+
+func TestForceRenewMessageWhileRebinding(t *testing.T) {
+	leases := newForceRenewLeases()
+	request := NewRequestMessage()
+	request.SetMacAddress("00:11:22:33:44:55")
+	ack := NewAckMessage(request, "192.0.2.10")
+	if _, err := leases.bind(request, ack); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if _, err := leases.bind(request, ack); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if _, err := leases.message("192.0.2.10"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+}
@@ -0,0 +1,204 @@
+package dhcp4
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	L "github.com/lsongdev/dhcp-go/dhcp4/options"
+)
+
+// Lease is a binding reported in answer to a leasequery (RFC 4388).
+type Lease struct {
+	IP           net.IP
+	HardwareAddr net.HardwareAddr
+	// ClientID is the data of the client identifier option the lease was
+	// bound to, or nil.
+	ClientID []byte
+	// Expires is when the lease ends, or zero when it is not known.
+	Expires time.Time
+	// LastTransaction is when the server last heard from the client, or
+	// zero when it is not known.
+	LastTransaction time.Time
+	// RelayAgentInformation is the relay agent information option of the
+	// client's last request. Bulk leasequery filters on its relay-id and
+	// remote-id.
+	RelayAgentInformation L.RelayAgentInformationOption
+}
+
+// LeaseStore is the lease state DefaultServerMux answers leasequeries
+// from.
+type LeaseStore interface {
+	// Manages reports whether ip is an address the server assigns.
+	Manages(ip net.IP) bool
+	// LeaseByIP returns the lease bound to ip.
+	LeaseByIP(ip net.IP) (Lease, bool)
+	// LeasesByHardwareAddr returns the leases bound to a hardware address.
+	LeasesByHardwareAddr(addr net.HardwareAddr) []Lease
+	// LeasesByClientID returns the leases bound to the data of a client
+	// identifier option.
+	LeasesByClientID(id []byte) []Lease
+}
+
+// isLeaseQueryReply reports whether t answers a DHCPLEASEQUERY.
+func isLeaseQueryReply(t L.MessageType) bool {
+	switch t {
+	case L.DHCPLEASEUNASSIGNED, L.DHCPLEASEUNKNOWN, L.DHCPLEASEACTIVE:
+		return true
+	}
+	return false
+}
+
+// isRequestorReply reports whether t answers a leasequery or bulk
+// leasequery. Such replies go to the requestor rather than the client
+// and carry the relay agent information of the lease.
+func isRequestorReply(t L.MessageType) bool {
+	switch t {
+	case L.DHCPLEASEQUERYDONE, L.DHCPLEASEQUERYSTATUS:
+		return true
+	}
+	return isLeaseQueryReply(t)
+}
+
+// serveLeaseQuery answers a DHCPLEASEQUERY from the lease store (RFC 4388
+// section 6.4). Queries are silently dropped when there is no store.
+func (d *DefaultServerMux) serveLeaseQuery(req *Message, rw ResponseWriter) {
+	if d.Leases == nil {
+		return
+	}
+	_ = rw.WriteResponse(newLeaseQueryReply(req, d.Leases, time.Now()))
+}
+
+func newLeaseQueryReply(req *Message, store LeaseStore, now time.Time) *Message {
+	reply := NewReplyMessage(req)
+	var leases []Lease
+	switch {
+	case !req.ClientIPAddr.Equal(net.IPv4zero):
+		// Query by IP address
+		reply.ClientIPAddr = req.ClientIPAddr
+		if lease, ok := store.LeaseByIP(req.ClientIPAddr); ok {
+			leases = []Lease{lease}
+		} else if store.Manages(req.ClientIPAddr) {
+			reply.SetMessageType(L.DHCPLEASEUNASSIGNED)
+			return reply
+		}
+	case req.GetOption(L.OptionCodeClientIdentifier) != nil:
+		leases = store.LeasesByClientID(req.GetOption(L.OptionCodeClientIdentifier).Encode())
+	case req.HardwareLength > 0:
+		leases = store.LeasesByHardwareAddr(req.ClientHardwareAddr)
+	}
+	if len(leases) == 0 {
+		reply.ClientIPAddr = net.IPv4zero
+		reply.SetMessageType(L.DHCPLEASEUNKNOWN)
+		return reply
+	}
+
+	// ciaddr holds the most recently used lease, associated-ip all of them.
+	latest := leases[0]
+	for _, lease := range leases[1:] {
+		if lease.LastTransaction.After(latest.LastTransaction) {
+			latest = lease
+		}
+	}
+	reply = newLeaseActiveMessage(req, latest, now)
+	if len(leases) > 1 {
+		option := L.AssociatedIPOption{}
+		for _, lease := range leases {
+			option.Addresses = append(option.Addresses, lease.IP)
+		}
+		reply.SetOption(option)
+	}
+	return reply
+}
+
+// newLeaseActiveMessage reports lease in a DHCPLEASEACTIVE reply to req.
+func newLeaseActiveMessage(req *Message, lease Lease, now time.Time) *Message {
+	reply := NewReplyMessage(req)
+	reply.SetMessageType(L.DHCPLEASEACTIVE)
+	reply.ClientIPAddr = lease.IP
+	if lease.HardwareAddr != nil {
+		reply.SetHardwareInfo(1, lease.HardwareAddr)
+	}
+	if lease.ClientID != nil {
+		reply.SetOption(L.ClientIdentifierOption{}.Decode(lease.ClientID))
+	}
+	if !lease.Expires.IsZero() {
+		reply.SetOption(L.NewLeaseTimeOption(durationSeconds(lease.Expires.Sub(now))))
+	}
+	if !lease.LastTransaction.IsZero() {
+		reply.SetOption(L.NewClientLastTransactionTimeOption(durationSeconds(now.Sub(lease.LastTransaction))))
+	}
+	if len(lease.RelayAgentInformation.SubOptions) > 0 {
+		reply.SetOption(lease.RelayAgentInformation)
+	}
+	return reply
+}
+
+func durationSeconds(d time.Duration) uint32 {
+	if d < 0 {
+		return 0
+	}
+	return uint32(d / time.Second)
+}
+
+// NewLeaseQueryByIPMessage queries the lease bound to ip.
+func NewLeaseQueryByIPMessage(ip string) *Message {
+	m := newLeaseQueryMessage()
+	m.ClientIPAddr = net.ParseIP(ip)
+	return m
+}
+
+// NewLeaseQueryByMacMessage queries the leases bound to a hardware
+// address.
+func NewLeaseQueryByMacMessage(mac string) *Message {
+	m := newLeaseQueryMessage()
+	m.SetMacAddress(mac)
+	return m
+}
+
+// NewLeaseQueryByClientIDMessage queries the leases bound to a client
+// identifier.
+func NewLeaseQueryByClientIDMessage(id L.Option) *Message {
+	m := newLeaseQueryMessage()
+	m.HardwareType = 0
+	m.HardwareLength = 0
+	m.ClientHardwareAddr = nil
+	m.SetOption(id)
+	return m
+}
+
+func newLeaseQueryMessage() *Message {
+	m := NewMessage()
+	m.OpCode = OpCodeBootRequest
+	m.SetMessageType(L.DHCPLEASEQUERY)
+	m.SetOption(L.NewParameterRequestOption([]L.OptionCode{
+		L.OptionCodeLeaseTime,
+		L.OptionCodeClientIdentifier,
+		L.OptionCodeClientLastTransactionTime,
+		L.OptionCodeAssociatedIP,
+	}))
+	return m
+}
+
+// LeaseQuery sends a leasequery built with one of the NewLeaseQuery
+// functions to the server and returns its DHCPLEASEACTIVE,
+// DHCPLEASEUNASSIGNED or DHCPLEASEUNKNOWN reply. The query's giaddr is
+// set to ClientIP, the address of the requestor, when it is not set.
+func (c *Client) LeaseQuery(query *Message) (reply *Message, err error) {
+	if query.GatewayIPAddr.Equal(net.IPv4zero) && c.config.ClientIP != "" {
+		query.GatewayIPAddr = net.ParseIP(c.config.ClientIP)
+	}
+	expectedXid := query.Xid
+	serverAddr := net.UDPAddr{IP: net.ParseIP(c.config.Server), Port: 67}
+	if err := c.SendMessage(&serverAddr, query); err != nil {
+		return nil, fmt.Errorf("failed to send leasequery message: %s", err)
+	}
+	reply, err = c.ReceiveWithXid(expectedXid)
+	if err != nil {
+		return nil, err
+	}
+	if !isLeaseQueryReply(reply.MessageType()) {
+		return nil, fmt.Errorf("unexpected leasequery reply: %s", reply.MessageType())
+	}
+	return reply, nil
+}
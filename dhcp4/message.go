@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net"
+	"time"
 
 	L "github.com/lsongdev/dhcp-go/dhcp4/options"
 )
@@ -36,22 +38,30 @@ var MagicCookie = []byte{0x63, 0x82, 0x53, 0x63}
 
 // Message represents a DHCP message.
 type Message struct {
-	OpCode             OpCodeType                `json:"op"`       // op(1 octet): Message op code / message type 1 = BOOTREQUEST, 2 = BOOTREPLY
-	HardwareType       uint8                     `json:"htype"`    // htype(1 octet): Hardware address type, see ARP section in "Assigned Numbers" RFC; e.g., ’1’ = 10mb ethernet
-	HardwareLength     uint8                     `json:"hlen"`     // hlen(1 octet): Hardware address length(e.g.  ’6’ for 10mb ethernet)
-	Hops               uint8                     `json:"hops"`     // hops(1 octet): Client sets to zero, optionally used by relay agents when booting via a relay agent.
-	Xid                uint32                    `json:"xid"`      // xid(4 octets): Transaction ID, a random number chosen by the client, used by the client and server to associate messages and responses between a client and a server
-	Seconds            uint16                    `json:"secs"`     // secs(2 octets): Filled in by client, seconds elapsed since client began address acquisition or renewal process.
-	Flags              uint16                    `json:"flags"`    // flags(2 octets): Bootp Flags
-	ClientIPAddr       net.IP                    `json:"ciaddr"`   // ciaddr(4 octets): Client IP address; only filled in if client is in BOUND, RENEW or REBINDING state and can respond to ARP requests
-	YourIPAddr         net.IP                    `json:"yiaddr"`   // yiaddr(4 octets): ’your’ (client) IP address
-	ServerIPAddr       net.IP                    `json:"siaddr"`   // siaddr(4 octets): IP address of next server to use in bootstrap; returned in DHCPOFFER, DHCPACK by server.
-	GatewayIPAddr      net.IP                    `json:"giaddr"`   // giaddr(4 octets): Relay agent IP address, used in booting via a relay agent
-	ClientHardwareAddr net.HardwareAddr          `json:"chaddr"`   // chaddr(16 octets): Client hardware address(6 octets) + Client hardware address padding(10 octets)
-	ServerHostName     string                    `json:"sname"`    // sname(64 octets): Optional server host name, null terminated string
-	BootFileName       string                    `json:"filename"` // file(128 octets): Boot file name, null terminated string; "generic" name or null in DHCPDISCOVER, fully qualified directory-path name in DHCPOFFER
-	MagicCookie        []byte                    `json:"cookie"`   // magicDhcp(4 octets): fixed value[63 92 53 63]
-	Options            map[L.OptionCode]L.Option `json:"options"`  // options(var): Optional parameters field
+	OpCode             OpCodeType       `json:"op"`       // op(1 octet): Message op code / message type 1 = BOOTREQUEST, 2 = BOOTREPLY
+	HardwareType       uint8            `json:"htype"`    // htype(1 octet): Hardware address type, see ARP section in "Assigned Numbers" RFC; e.g., ’1’ = 10mb ethernet
+	HardwareLength     uint8            `json:"hlen"`     // hlen(1 octet): Hardware address length(e.g.  ’6’ for 10mb ethernet)
+	Hops               uint8            `json:"hops"`     // hops(1 octet): Client sets to zero, optionally used by relay agents when booting via a relay agent.
+	Xid                uint32           `json:"xid"`      // xid(4 octets): Transaction ID, a random number chosen by the client, used by the client and server to associate messages and responses between a client and a server
+	Seconds            uint16           `json:"secs"`     // secs(2 octets): Filled in by client, seconds elapsed since client began address acquisition or renewal process.
+	Flags              uint16           `json:"flags"`    // flags(2 octets): Bootp Flags
+	ClientIPAddr       net.IP           `json:"ciaddr"`   // ciaddr(4 octets): Client IP address; only filled in if client is in BOUND, RENEW or REBINDING state and can respond to ARP requests
+	YourIPAddr         net.IP           `json:"yiaddr"`   // yiaddr(4 octets): ’your’ (client) IP address
+	ServerIPAddr       net.IP           `json:"siaddr"`   // siaddr(4 octets): IP address of next server to use in bootstrap; returned in DHCPOFFER, DHCPACK by server.
+	GatewayIPAddr      net.IP           `json:"giaddr"`   // giaddr(4 octets): Relay agent IP address, used in booting via a relay agent
+	ClientHardwareAddr net.HardwareAddr `json:"chaddr"`   // chaddr(16 octets): Client hardware address(6 octets) + Client hardware address padding(10 octets)
+	ServerHostName     string           `json:"sname"`    // sname(64 octets): Optional server host name, null terminated string
+	BootFileName       string           `json:"filename"` // file(128 octets): Boot file name, null terminated string; "generic" name or null in DHCPDISCOVER, fully qualified directory-path name in DHCPOFFER
+	MagicCookie        []byte           `json:"cookie"`   // magicDhcp(4 octets): fixed value[63 92 53 63]
+	Options            L.Options        `json:"options"`  // options(var): Optional parameters field, in wire order
+
+	// InterfaceAddr is the address of the server interface the message
+	// was received on, when known. It is not part of the wire format.
+	InterfaceAddr net.IP `json:"-"`
+	// OptionErrors lists the options FromBytes could not decode. Each
+	// such option is kept in Options as a RawOption with its original
+	// data.
+	OptionErrors []*L.OptionError `json:"-"`
 }
 
 // NewMessage creates a new DHCP message with default values.
@@ -72,7 +82,7 @@ func NewMessage() (m *Message) {
 		ServerHostName:     "",
 		BootFileName:       "",
 		MagicCookie:        MagicCookie,
-		Options:            make(map[L.OptionCode]L.Option),
+		Options:            L.Options{},
 	}
 	return
 }
@@ -114,6 +124,14 @@ func NewAckMessage(req *Message, ip string) *Message {
 	return ack
 }
 
+// NewRapidCommitAckMessage acknowledges a DHCPDISCOVER that asked for
+// rapid commit (RFC 4039).
+func NewRapidCommitAckMessage(discover *Message, ip string) *Message {
+	ack := NewAckMessage(discover, ip)
+	ack.SetOption(L.NewRapidCommitOption())
+	return ack
+}
+
 func NewNakMessage(req *Message, reason string) *Message {
 	nak := NewReplyMessage(req)
 	nak.SetOption(L.NewMessageType(L.DHCPNAK))
@@ -164,65 +182,197 @@ func NewInformMessage() *Message {
 	return msg
 }
 
-// FromBytes decodes the DHCP message from bytes.
+// Errors reported by FromBytes for malformed messages. They are wrapped
+// in a *DecodeError that records where decoding stopped.
+var (
+	ErrTruncated         = errors.New("dhcp4: truncated message")
+	ErrBadCookie         = errors.New("dhcp4: bad magic cookie")
+	ErrOptionOverrun     = errors.New("dhcp4: option overruns message")
+	ErrBadHardwareLength = errors.New("dhcp4: bad hardware address length")
+)
+
+// ErrMessageTooLarge is returned when a message cannot be encoded within
+// the requested size.
+var ErrMessageTooLarge = errors.New("dhcp4: message too large")
+
+// DecodeError describes a malformed DHCP message.
+type DecodeError struct {
+	Offset int   // byte offset at which decoding failed
+	Err    error // one of the Err* decoding errors or an *options.OptionError
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Err, e.Offset)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+const (
+	// snameOffset and fileOffset are where the sname and file fields start.
+	snameOffset = 44
+	fileOffset  = 108
+	// headerLength is the size of the fixed BOOTP header up to the magic cookie.
+	headerLength = 236
+	// cookieOffset is where the magic cookie starts.
+	cookieOffset = headerLength
+	// optionsOffset is where the options field starts.
+	optionsOffset = cookieOffset + 4
+	// defaultMaxMessageSize is the datagram size every client must accept.
+	defaultMaxMessageSize = 576
+	// ipUDPHeaderLength is the size of the IPv4 and UDP headers.
+	ipUDPHeaderLength = 20 + 8
+	// minBOOTPMessageSize is the smallest BOOTP message; some relays drop
+	// anything shorter (RFC 1542 section 2.1).
+	minBOOTPMessageSize = 300
+)
+
+// FromBytes decodes the DHCP message from bytes. Malformed framing and a
+// malformed message type option are rejected with a *DecodeError. Other
+// malformed options are recorded in OptionErrors and do not fail the
+// message.
 func FromBytes(data []byte) (m *Message, err error) {
+	if len(data) < optionsOffset {
+		return nil, &DecodeError{Offset: len(data), Err: ErrTruncated}
+	}
+	// Work on a private copy so the message never aliases the caller's
+	// buffer, which servers reuse between reads.
+	data = append([]byte(nil), data...)
 	m = NewMessage()
-	reader := bytes.NewReader(data)
-	// Read fixed-length fields
-	binary.Read(reader, binary.BigEndian, &m.OpCode)
-	binary.Read(reader, binary.BigEndian, &m.HardwareType)
-	binary.Read(reader, binary.BigEndian, &m.HardwareLength)
-	binary.Read(reader, binary.BigEndian, &m.Hops)
-	binary.Read(reader, binary.BigEndian, &m.Xid)
-	binary.Read(reader, binary.BigEndian, &m.Seconds)
-	binary.Read(reader, binary.BigEndian, &m.Flags)
+	m.OpCode = OpCodeType(data[0])
+	m.HardwareType = data[1]
+	m.HardwareLength = data[2]
+	m.Hops = data[3]
+	m.Xid = binary.BigEndian.Uint32(data[4:8])
+	m.Seconds = binary.BigEndian.Uint16(data[8:10])
+	m.Flags = binary.BigEndian.Uint16(data[10:12])
+	if m.HardwareLength > 16 {
+		return nil, &DecodeError{Offset: 2, Err: ErrBadHardwareLength}
+	}
 
 	// Read IP addresses
-	m.ClientIPAddr = readIP(reader)
-	m.YourIPAddr = readIP(reader)
-	m.ServerIPAddr = readIP(reader)
-	m.GatewayIPAddr = readIP(reader)
+	m.ClientIPAddr = net.IP(data[12:16])
+	m.YourIPAddr = net.IP(data[16:20])
+	m.ServerIPAddr = net.IP(data[20:24])
+	m.GatewayIPAddr = net.IP(data[24:28])
 
 	// Read ClientHardwareAddr
-	m.ClientHardwareAddr = readBytes(reader, 16)
-	m.ClientHardwareAddr = m.ClientHardwareAddr[:m.HardwareLength]
+	m.ClientHardwareAddr = net.HardwareAddr(data[28 : 28+int(m.HardwareLength)])
 	//
-	m.ServerHostName = readString(reader, 64)
-	m.BootFileName = readString(reader, 128)
-	m.MagicCookie = readBytes(reader, 4)
-	for {
-		code, err := reader.ReadByte()
-		if err != nil {
-			break
-		}
-		if code == 0xFF {
-			break
-		}
-		length, _ := reader.ReadByte()
-		data := readBytes(reader, int(length))
-		c := L.OptionCode(code)
-		m.Options[c] = L.ParseOption(c, data)
+	m.ServerHostName = readString(data[snameOffset:fileOffset])
+	m.BootFileName = readString(data[fileOffset:headerLength])
+	m.MagicCookie = data[cookieOffset:optionsOffset]
+	if !bytes.Equal(m.MagicCookie, MagicCookie) {
+		return nil, &DecodeError{Offset: cookieOffset, Err: ErrBadCookie}
 	}
-	return
+	if err = m.decodeOptions(data); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
-func readBytes(reader *bytes.Reader, length int) []byte {
-	bytes := make([]byte, length)
-	reader.Read(bytes)
-	return bytes
+// rawOption is the undecoded data of an option, concatenated across all
+// of its instances as described in RFC 3396.
+type rawOption struct {
+	code   L.OptionCode
+	offset int // offset of the first instance
+	data   []byte
+}
 
+// scanOptions collects the options in data starting at offset until an
+// End option or the end of data. Repeated instances of a code are
+// concatenated into the first one.
+func scanOptions(raw []rawOption, data []byte, offset int) ([]rawOption, error) {
+	for offset < len(data) {
+		code := L.OptionCode(data[offset])
+		if code == L.OptionCodeEnd {
+			return raw, nil
+		}
+		if code == L.OptionCodePad {
+			offset++
+			continue
+		}
+		if offset+1 >= len(data) {
+			return nil, &DecodeError{Offset: offset, Err: ErrTruncated}
+		}
+		length := int(data[offset+1])
+		if offset+2+length > len(data) {
+			return nil, &DecodeError{Offset: offset, Err: ErrOptionOverrun}
+		}
+		// Cap the slice so concatenation copies instead of overwriting
+		// the bytes that follow in data.
+		value := data[offset+2 : offset+2+length : offset+2+length]
+		found := false
+		for i := range raw {
+			if raw[i].code == code {
+				raw[i].data = append(raw[i].data, value...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			raw = append(raw, rawOption{code: code, offset: offset, data: value})
+		}
+		offset += 2 + length
+	}
+	return raw, nil
 }
 
-func readIP(reader *bytes.Reader) net.IP {
-	return readBytes(reader, net.IPv4len)
+// decodeOptions parses the options field of data, followed by the file
+// and sname fields when they are overloaded.
+func (m *Message) decodeOptions(data []byte) error {
+	raw, err := scanOptions(nil, data, optionsOffset)
+	if err != nil {
+		return err
+	}
+	if raw, err = m.scanOverload(raw, data); err != nil {
+		return err
+	}
+	for _, r := range raw {
+		option, err := L.DecodeOption(r.code, r.data)
+		if err != nil {
+			// The message type is needed to dispatch the message.
+			if r.code == L.OptionCodeMessageType {
+				return &DecodeError{Offset: r.offset, Err: err}
+			}
+			m.OptionErrors = append(m.OptionErrors, err.(*L.OptionError))
+		}
+		m.Options.Add(option)
+	}
+	return nil
 }
 
-func readString(reader *bytes.Reader, length int) string {
-	return string(bytes.TrimRight(readBytes(reader, length), "\x00"))
+func readString(data []byte) string {
+	return string(bytes.TrimRight(data, "\x00"))
 }
 
 // Encode encodes the DHCP message to bytes.
 func (m *Message) Bytes() []byte {
+	data, _ := m.BytesWithLimit(0)
+	return data
+}
+
+// BytesWithLimit encodes the DHCP message in at most maxSize bytes, or
+// without limit if maxSize is not positive. Options that do not fit in
+// the options field overload the unused sname and file fields. It returns
+// ErrMessageTooLarge if the options still do not fit.
+func (m *Message) BytesWithLimit(maxSize int) ([]byte, error) {
+	ordered := m.orderedOptions()
+	options := new(bytes.Buffer)
+	// write options, message type first
+	for _, option := range ordered {
+		writeOption(options, option.Code(), option.Encode())
+	}
+	sname := []byte(m.ServerHostName)
+	file := []byte(m.BootFileName)
+	if maxSize > 0 && optionsOffset+options.Len()+1 > maxSize {
+		var err error
+		if options, sname, file, err = m.overload(ordered, maxSize); err != nil {
+			return nil, err
+		}
+	}
+
 	buf := new(bytes.Buffer)
 	binary.Write(buf, binary.BigEndian, m.OpCode)
 	binary.Write(buf, binary.BigEndian, m.HardwareType)
@@ -241,26 +391,57 @@ func (m *Message) Bytes() []byte {
 	copy(hardwareBytes, m.ClientHardwareAddr)
 	buf.Write(hardwareBytes)
 
-	hostnameBytes := make([]byte, 64)
-	copy(hostnameBytes, []byte(m.ServerHostName))
+	hostnameBytes := make([]byte, fileOffset-snameOffset)
+	copy(hostnameBytes, sname)
 	buf.Write(hostnameBytes)
 
-	filenameBytes := make([]byte, 128)
-	copy(filenameBytes, []byte(m.BootFileName))
+	filenameBytes := make([]byte, headerLength-fileOffset)
+	copy(filenameBytes, file)
 	buf.Write(filenameBytes)
 
 	// MagicCookie
 	buf.Write(m.MagicCookie)
-	// write options
-	for code, option := range m.Options {
-		data := option.Encode()
+	buf.Write(options.Bytes())
+	// EndOption
+	buf.WriteByte(byte(L.OptionCodeEnd))
+	return buf.Bytes(), nil
+}
+
+// maxOptionLength is the largest value a single option instance can carry.
+const maxOptionLength = 255
+
+// writeOption writes an option to buf, splitting values longer than 255
+// bytes into consecutive instances as described in RFC 3396.
+func writeOption(buf *bytes.Buffer, code L.OptionCode, data []byte) {
+	for {
+		n := len(data)
+		if n > maxOptionLength {
+			n = maxOptionLength
+		}
 		buf.WriteByte(byte(code))
-		buf.WriteByte(byte(len(data)))
-		buf.Write(data)
+		buf.WriteByte(byte(n))
+		buf.Write(data[:n])
+		data = data[n:]
+		if len(data) == 0 {
+			return
+		}
 	}
-	// EndOption
-	buf.WriteByte(255)
-	return buf.Bytes()
+}
+
+// orderedOptions returns the options in encoding order: the DHCP message
+// type first, then the authentication option so that overloading never
+// splits it and its MAC can be filled in place, followed by the remaining
+// options in their stored order.
+func (m *Message) orderedOptions() L.Options {
+	ordered := make(L.Options, 0, len(m.Options))
+	ordered = append(ordered, m.Options.GetAll(L.OptionCodeMessageType)...)
+	ordered = append(ordered, m.Options.GetAll(L.OptionCodeAuthentication)...)
+	for _, option := range m.Options {
+		if code := option.Code(); code != L.OptionCodeMessageType && code != L.OptionCodeAuthentication {
+			ordered = append(ordered, option)
+		}
+	}
+	return ordered
 }
 
 func (m Message) String() string {
@@ -282,8 +463,8 @@ func (m Message) String() string {
 	buffer.WriteString(fmt.Sprintf("  BootFileName: %s\n", m.BootFileName))
 	buffer.WriteString(fmt.Sprintf("  MagicCookie: %v\n", hex.EncodeToString(m.MagicCookie)))
 	buffer.WriteString("  Options:\n")
-	for code, option := range m.Options {
-		buffer.WriteString(fmt.Sprintf("    Code: %d, %v\n", code, option))
+	for _, option := range m.Options {
+		buffer.WriteString(fmt.Sprintf("    Code: %d, %v\n", option.Code(), option))
 	}
 	return buffer.String()
 }
@@ -295,11 +476,17 @@ func (m *Message) SetHardwareInfo(t uint8, h net.HardwareAddr) {
 }
 
 func (m *Message) SetOption(option L.Option) {
-	m.Options[option.Code()] = option
+	m.Options.Set(option)
+}
+
+// AddOption appends option even if the message already has one with the
+// same code.
+func (m *Message) AddOption(option L.Option) {
+	m.Options.Add(option)
 }
 
 func (m *Message) GetOption(code L.OptionCode) L.Option {
-	return m.Options[code]
+	return m.Options.Get(code)
 }
 
 func (m *Message) GetHostName() string {
@@ -329,6 +516,15 @@ func (m *Message) GetMacAddress() string {
 	return m.ClientHardwareAddr.String()
 }
 
+// ClientID identifies the client: the client identifier option when
+// present (RFC 2131 section 4.2), otherwise the hardware address.
+func (m *Message) ClientID() string {
+	if option := m.GetOption(L.OptionCodeClientIdentifier); option != nil {
+		return "id:" + hex.EncodeToString(option.Encode())
+	}
+	return "hw:" + m.GetMacAddress()
+}
+
 func (m *Message) MessageType() L.MessageType {
 	option, ok := m.GetOption(L.OptionCodeMessageType).(L.MessageTypeOption)
 	if !ok {
@@ -365,6 +561,242 @@ func (m *Message) GetLeaseTime() uint32 {
 	return option.LeaseTime
 }
 
+// VendorClass returns the client's vendor class identifier, or "".
+func (m *Message) VendorClass() string {
+	option, ok := m.GetOption(L.OptionCodeVendorClassIdentifier).(L.VendorClassIdentifierOption)
+	if !ok {
+		return ""
+	}
+	return option.VendorClass
+}
+
+// VendorSpecificInformation returns the vendor-specific information
+// option. When sub-option types are registered for the message's vendor
+// class, its SubOptions are decoded with them.
+func (m *Message) VendorSpecificInformation() (L.VendorSpecificInformationOption, bool) {
+	option, ok := m.GetOption(L.OptionCodeVendorSpecificInformation).(L.VendorSpecificInformationOption)
+	if !ok {
+		return option, false
+	}
+	if decoded, err := option.DecodeVendor(m.VendorClass()); err == nil {
+		return decoded, true
+	}
+	return option, true
+}
+
+// SubnetSelection returns the subnet requested with the subnet
+// selection option (RFC 3011), or nil.
+func (m *Message) SubnetSelection() net.IP {
+	option, ok := m.GetOption(L.OptionCodeSubnetSelection).(L.SubnetSelectionOption)
+	if !ok {
+		return nil
+	}
+	return option.Subnet
+}
+
+// EffectiveLinkAddress returns the address identifying the link the
+// client is on, for choosing the subnet to allocate from. In order of
+// precedence it is the subnet selection option (RFC 3011), the relay
+// agent link selection sub-option (RFC 3527), giaddr and finally the
+// address of the interface the message was received on. It returns nil
+// when none is known.
+func (m *Message) EffectiveLinkAddress() net.IP {
+	if subnet := m.SubnetSelection(); subnet != nil {
+		return subnet
+	}
+	if relay, ok := m.RelayAgentInformation(); ok {
+		if link := relay.LinkSelection(); link != nil {
+			return link
+		}
+	}
+	if m.GatewayIPAddr != nil && !m.GatewayIPAddr.Equal(net.IPv4zero) {
+		return m.GatewayIPAddr
+	}
+	if m.InterfaceAddr != nil && !m.InterfaceAddr.IsUnspecified() {
+		return m.InterfaceAddr
+	}
+	return nil
+}
+
+// UserClass returns the user class option (RFC 3004), if any.
+func (m *Message) UserClass() (L.UserClassOption, bool) {
+	option, ok := m.GetOption(L.OptionCodeUserClass).(L.UserClassOption)
+	return option, ok
+}
+
+// HasUserClass reports whether the client sent class as a user class.
+func (m *Message) HasUserClass(class string) bool {
+	option, _ := m.UserClass()
+	return option.Has(class)
+}
+
+// VIVendorClass returns the vendor-identifying vendor class option
+// (RFC 3925), if any.
+func (m *Message) VIVendorClass() (L.VIVendorClassOption, bool) {
+	option, ok := m.GetOption(L.OptionCodeVIVendorClass).(L.VIVendorClassOption)
+	return option, ok
+}
+
+// HasVIVendorClass reports whether the client sent class for
+// enterpriseNumber in the vendor-identifying vendor class option.
+func (m *Message) HasVIVendorClass(enterpriseNumber uint32, class string) bool {
+	option, _ := m.VIVendorClass()
+	return option.Has(enterpriseNumber, class)
+}
+
+// VIVendorSpecificInformation returns the vendor-identifying
+// vendor-specific information option (RFC 3925), if any.
+func (m *Message) VIVendorSpecificInformation() (L.VIVendorSpecificInformationOption, bool) {
+	option, ok := m.GetOption(L.OptionCodeVIVendorSpecificInformation).(L.VIVendorSpecificInformationOption)
+	return option, ok
+}
+
+// ClientLastTransactionTime returns how long before a DHCPLEASEACTIVE
+// reply the server last heard from the client (RFC 4388).
+func (m *Message) ClientLastTransactionTime() (time.Duration, bool) {
+	option, ok := m.GetOption(L.OptionCodeClientLastTransactionTime).(L.ClientLastTransactionTimeOption)
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(option.Seconds) * time.Second, true
+}
+
+// AssociatedIPs returns the addresses bound to the client reported in a
+// DHCPLEASEACTIVE reply (RFC 4388), or nil.
+func (m *Message) AssociatedIPs() []net.IP {
+	option, ok := m.GetOption(L.OptionCodeAssociatedIP).(L.AssociatedIPOption)
+	if !ok {
+		return nil
+	}
+	return option.Addresses
+}
+
+// RapidCommit reports whether the message carries the rapid commit
+// option (RFC 4039).
+func (m *Message) RapidCommit() bool {
+	return m.Options.Has(L.OptionCodeRapidCommit)
+}
+
+// RequestsOption reports whether code is in the message's parameter
+// request list.
+func (m *Message) RequestsOption(code L.OptionCode) bool {
+	prl, ok := m.GetOption(L.OptionCodeParameterRequest).(L.ParameterRequestOption)
+	if !ok {
+		return false
+	}
+	for _, requested := range prl.Parameters {
+		if requested == code {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestOption adds code to the message's parameter request list.
+func (m *Message) RequestOption(code L.OptionCode) {
+	if m.RequestsOption(code) {
+		return
+	}
+	prl, _ := m.GetOption(L.OptionCodeParameterRequest).(L.ParameterRequestOption)
+	parameters := append(append([]L.OptionCode(nil), prl.Parameters...), code)
+	m.SetOption(L.NewParameterRequestOption(parameters))
+}
+
+// IPv6OnlyPreferred returns the IPv6-only preferred option (RFC 8925),
+// if any.
+func (m *Message) IPv6OnlyPreferred() (L.IPv6OnlyPreferredOption, bool) {
+	option, ok := m.GetOption(L.OptionCodeIPv6OnlyPreferred).(L.IPv6OnlyPreferredOption)
+	return option, ok
+}
+
+// CaptivePortal returns the captive portal URI (RFC 8910), or "".
+func (m *Message) CaptivePortal() string {
+	option, ok := m.GetOption(L.OptionCodeCaptivePortal).(L.CaptivePortalOption)
+	if !ok {
+		return ""
+	}
+	return option.URI
+}
+
+// ClientArchitectures returns the pre-boot architectures listed by a
+// network boot client (RFC 4578), or nil.
+func (m *Message) ClientArchitectures() []L.ClientArchitecture {
+	option, _ := m.GetOption(L.OptionCodeClientSystemArchitecture).(L.ClientSystemArchitectureOption)
+	return option.Architectures
+}
+
+// ClientArchitecture returns the client's preferred pre-boot
+// architecture, which is the first one it listed.
+func (m *Message) ClientArchitecture() (L.ClientArchitecture, bool) {
+	architectures := m.ClientArchitectures()
+	if len(architectures) == 0 {
+		return 0, false
+	}
+	return architectures[0], true
+}
+
+// SetBootFile points a network boot client at file on server. It sets
+// the siaddr and file header fields and the matching TFTP server name
+// and bootfile name options, so clients reading either agree.
+func (m *Message) SetBootFile(server net.IP, file string) {
+	m.ServerIPAddr = server
+	m.BootFileName = file
+	m.SetOption(L.NewTFTPServerNameOption(server.String()))
+	m.SetOption(L.NewBootFileNameOption(file))
+}
+
+// ClientFQDN returns the client FQDN option, if any.
+func (m *Message) ClientFQDN() (L.ClientFQDNOption, bool) {
+	option, ok := m.GetOption(L.OptionCodeClientFullyQualifiedDomainName).(L.ClientFQDNOption)
+	return option, ok
+}
+
+// RelayAgentInformation returns the relay agent information option
+// inserted by a relay, if any.
+func (m *Message) RelayAgentInformation() (L.RelayAgentInformationOption, bool) {
+	option, ok := m.GetOption(L.OptionCodeRelayAgentInformation).(L.RelayAgentInformationOption)
+	return option, ok
+}
+
+// CircuitID returns the circuit ID added by the relay agent, or nil.
+func (m *Message) CircuitID() []byte {
+	option, _ := m.RelayAgentInformation()
+	return option.CircuitID()
+}
+
+// RemoteID returns the remote ID added by the relay agent, or nil.
+func (m *Message) RemoteID() []byte {
+	option, _ := m.RelayAgentInformation()
+	return option.RemoteID()
+}
+
+// ClasslessStaticRoutes returns the routes from the classless static
+// route option, falling back to the Microsoft alias (code 249). Clients
+// that receive classless routes must ignore the router and static route
+// options (RFC 3442).
+func (m *Message) ClasslessStaticRoutes() []L.ClasslessRoute {
+	for _, code := range []L.OptionCode{L.OptionCodeClasslessStaticRoute, L.OptionCodeMicrosoftClasslessStaticRoute} {
+		if option, ok := m.GetOption(code).(L.ClasslessStaticRouteOption); ok {
+			return option.Routes
+		}
+	}
+	return nil
+}
+
+// MaxMessageSize returns the largest DHCP message the sender accepts,
+// from its maximum DHCP message size option or the 576 octets every
+// client must accept. Like most servers it counts the IP and UDP headers
+// as part of the limit.
+func (m *Message) MaxMessageSize() int {
+	size := defaultMaxMessageSize
+	if option, ok := m.GetOption(L.OptionCodeMaximumMessageSize).(L.MaximumMessageSizeOption); ok {
+		if int(option.MaximumMessageSize) > size {
+			size = int(option.MaximumMessageSize)
+		}
+	}
+	return size - ipUDPHeaderLength
+}
+
 func (m *Message) SetServerIP(ip string) {
 	m.ServerIPAddr = net.ParseIP(ip)
 }
@@ -0,0 +1,440 @@
+package dhcp4
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/lsongdev/dhcp-go/dhcp4/options"
+)
+
+func TestFromBytesRejectsMalformed(t *testing.T) {
+	valid := NewDiscoverMessage().Bytes()
+
+	badCookie := append([]byte(nil), valid...)
+	badCookie[cookieOffset] = 0
+
+	badHardwareLength := append([]byte(nil), valid...)
+	badHardwareLength[2] = 17
+
+	overrun := append([]byte(nil), valid[:optionsOffset]...)
+	overrun = append(overrun, 12, 10, 'h', 'o', 's', 't')
+
+	tests := []struct {
+		name   string
+		data   []byte
+		err    error
+		offset int
+	}{
+		{"truncated", valid[:100], ErrTruncated, 100},
+		{"bad cookie", badCookie, ErrBadCookie, cookieOffset},
+		{"bad hardware length", badHardwareLength, ErrBadHardwareLength, 2},
+		{"option overrun", overrun, ErrOptionOverrun, optionsOffset},
+		{"missing option length", valid[:optionsOffset+1], ErrTruncated, optionsOffset},
+	}
+	for _, tt := range tests {
+		_, err := FromBytes(tt.data)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Offset != tt.offset {
+			t.Errorf("%s: err = %v, want offset %d", tt.name, err, tt.offset)
+		}
+	}
+}
+
+func TestFromBytesDoesNotAliasInput(t *testing.T) {
+	data := NewDiscoverMessage().Bytes()
+	m, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range data {
+		data[i] = 0xff
+	}
+	if !bytes.Equal(m.MagicCookie, MagicCookie) || m.MessageType().String() != "Discover" {
+		t.Fatal("decoded message changed with input buffer")
+	}
+}
+
+func TestFromBytesRejectsMalformedOption(t *testing.T) {
+	data := NewDiscoverMessage().Bytes()
+	data = append(data[:optionsOffset], 53, 0, 255)
+	_, err := FromBytes(data)
+	var optionErr *options.OptionError
+	if !errors.As(err, &optionErr) || optionErr.Code != options.OptionCodeMessageType {
+		t.Fatalf("err = %v, want option 53 error", err)
+	}
+}
+
+func TestFromBytesKeepsMalformedOptionalOptions(t *testing.T) {
+	data := NewDiscoverMessage().Bytes()
+	data = append(data[:optionsOffset], 53, 1, 1, 12, 0, 26, 2, 0, 60, 255)
+	m, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MessageType() != options.DHCPDISCOVER {
+		t.Fatalf("message type = %s", m.MessageType())
+	}
+	if len(m.OptionErrors) != 2 ||
+		m.OptionErrors[0].Code != options.OptionCodeHostName ||
+		m.OptionErrors[1].Code != options.OptionCodeInterfaceMTU {
+		t.Fatalf("option errors = %v", m.OptionErrors)
+	}
+	raw, ok := m.GetOption(options.OptionCodeInterfaceMTU).(options.RawOption)
+	if !ok || !bytes.Equal(raw.Encode(), []byte{0, 60}) {
+		t.Fatalf("option 26 = %#v, want raw data", m.GetOption(options.OptionCodeInterfaceMTU))
+	}
+	if !bytes.Equal(m.Bytes()[optionsOffset:optionsOffset+10], data[optionsOffset:]) {
+		t.Fatal("malformed options changed on re-encode")
+	}
+}
+
+func FuzzFromBytes(f *testing.F) {
+	discover := NewDiscoverMessage()
+	discover.SetHostName("host")
+	discover.SetOption(options.NewParameterRequestOption([]options.OptionCode{1, 3, 6}))
+	f.Add(discover.Bytes())
+	offer := NewOfferMessage(discover, "192.0.2.10")
+	offer.SetOption(options.NewRouterOption([]string{"192.0.2.1"}))
+	offer.SetOption(options.NewClientIdentifierOptionWithMac("00:11:22:33:44:55"))
+	f.Add(offer.Bytes())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m, err := FromBytes(data)
+		if err != nil {
+			return
+		}
+		encoded := m.Bytes()
+		decoded, err := FromBytes(encoded)
+		if err != nil {
+			t.Fatalf("re-decode: %v", err)
+		}
+		if !bytes.Equal(decoded.Bytes(), encoded) {
+			t.Fatal("message changed across round trip")
+		}
+	})
+}
+
+func TestBytesKeepsOptionOrder(t *testing.T) {
+	m := NewMessage()
+	m.SetOption(options.NewHostNameOption("host"))
+	m.SetOption(options.NewRouterOption([]string{"192.0.2.1"}))
+	m.SetOption(options.NewDomainNameOption("example.com"))
+	m.SetMessageType(options.DHCPDISCOVER)
+	data := m.Bytes()
+	if data[optionsOffset] != byte(options.OptionCodeMessageType) {
+		t.Fatalf("first option = %d, want 53", data[optionsOffset])
+	}
+	decoded, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var codes []options.OptionCode
+	for _, option := range decoded.Options {
+		codes = append(codes, option.Code())
+	}
+	want := []options.OptionCode{53, 12, 3, 15}
+	if fmt.Sprint(codes) != fmt.Sprint(want) {
+		t.Fatalf("codes = %v, want %v", codes, want)
+	}
+	if !bytes.Equal(decoded.Bytes(), data) {
+		t.Fatal("re-encoded message differs")
+	}
+}
+
+func TestLongOptionsAreSplitAndConcatenated(t *testing.T) {
+	var routers []string
+	for i := 0; i < 100; i++ {
+		routers = append(routers, fmt.Sprintf("10.0.%d.1", i))
+	}
+	m := NewDiscoverMessage()
+	m.SetOption(options.NewRouterOption(routers))
+	data := m.Bytes()
+	// 400 bytes of routers need two instances: 255 + 145.
+	second := optionsOffset + 3 + 2 + 255
+	if data[second] != byte(options.OptionCodeRouter) || data[second+1] != 145 {
+		t.Fatalf("second instance header = %v", data[second:second+2])
+	}
+	decoded, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, ok := decoded.GetOption(options.OptionCodeRouter).(options.RouterOption)
+	if !ok || len(router.Routers) != len(routers) {
+		t.Fatalf("decoded %v", decoded.GetOption(options.OptionCodeRouter))
+	}
+	if router.Routers[99].String() != "10.0.99.1" {
+		t.Fatalf("last router = %s", router.Routers[99])
+	}
+}
+
+func TestRepeatedOptionsAreConcatenated(t *testing.T) {
+	m := NewDiscoverMessage()
+	m.SetOption(options.NewRouterOption([]string{"192.0.2.1"}))
+	m.AddOption(options.NewRouterOption([]string{"192.0.2.2"}))
+	decoded, err := FromBytes(m.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(decoded.Options.GetAll(options.OptionCodeRouter)); n != 1 {
+		t.Fatalf("router options = %d, want 1", n)
+	}
+	router := decoded.GetOption(options.OptionCodeRouter).(options.RouterOption)
+	if len(router.Routers) != 2 || router.Routers[1].String() != "192.0.2.2" {
+		t.Fatalf("routers = %v", router.Routers)
+	}
+}
+
+func TestSplitOptionsDoNotOverwriteFollowingOptions(t *testing.T) {
+	data := NewDiscoverMessage().Bytes()
+	data = append(data[:optionsOffset],
+		53, 1, 1,
+		3, 4, 192, 0, 2, 1,
+		12, 4, 'h', 'o', 's', 't',
+		3, 4, 192, 0, 2, 2,
+		255)
+	m, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := m.GetOption(options.OptionCodeHostName); name == nil || string(name.Encode()) != "host" {
+		t.Fatalf("hostname = %v, want host", name)
+	}
+	router := m.GetOption(options.OptionCodeRouter).(options.RouterOption)
+	if len(router.Routers) != 2 || router.Routers[1].String() != "192.0.2.2" {
+		t.Fatalf("routers = %v", router.Routers)
+	}
+}
+
+func TestBytesWithLimitOverloadsFields(t *testing.T) {
+	var routers []string
+	for i := 0; i < 150; i++ {
+		routers = append(routers, fmt.Sprintf("10.0.%d.1", i))
+	}
+	m := NewOfferMessage(NewDiscoverMessage(), "192.0.2.10")
+	m.SetOption(options.NewRouterOption(routers))
+	m.SetHostName("host")
+	if _, err := m.BytesWithLimit(576); err != ErrMessageTooLarge {
+		t.Fatalf("err = %v, want ErrMessageTooLarge", err)
+	}
+
+	routers = routers[:80]
+	m.SetOption(options.NewRouterOption(routers))
+	data, err := m.BytesWithLimit(576)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > 576 {
+		t.Fatalf("len = %d, want <= 576", len(data))
+	}
+	decoded, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.GetOption(options.OptionCodeOverload) != nil {
+		t.Fatal("overload option kept after decoding")
+	}
+	router, ok := decoded.GetOption(options.OptionCodeRouter).(options.RouterOption)
+	if !ok || len(router.Routers) != len(routers) {
+		t.Fatalf("decoded %v", decoded.GetOption(options.OptionCodeRouter))
+	}
+	if decoded.GetHostName() != "host" || decoded.GetLeaseTime() == 0 {
+		t.Fatalf("lost options: %s", decoded)
+	}
+	if !bytes.Equal(decoded.Bytes(), m.Bytes()) {
+		t.Fatal("overloaded message decodes differently")
+	}
+}
+
+func TestOverloadOptionInOverloadedFieldIsIgnored(t *testing.T) {
+	data := NewDiscoverMessage().Bytes()
+	file := append([]byte{52, 1, 3, 12, 4, 'h', 'o', 's', 't', 255}, make([]byte, headerLength-fileOffset-10)...)
+	copy(data[fileOffset:headerLength], file)
+	data = append(data[:optionsOffset], 53, 1, 1, 52, 1, 1, 255)
+	m, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.GetOption(options.OptionCodeOverload) != nil {
+		t.Fatal("kept option overload from the file field")
+	}
+	if m.GetHostName() != "host" {
+		t.Fatalf("hostname = %q, want host", m.GetHostName())
+	}
+	decoded, err := FromBytes(m.Bytes())
+	if err != nil {
+		t.Fatalf("re-decode: %v", err)
+	}
+	if decoded.GetHostName() != "host" || decoded.ServerHostName != "" {
+		t.Fatalf("re-decoded %s", decoded)
+	}
+}
+
+func mustTypedOption(t *testing.T, code options.OptionCode, typ options.OptionType, value interface{}) options.Option {
+	t.Helper()
+	option, err := options.NewTypedOption(code, typ, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return option
+}
+
+func TestVendorSpecificInformationUsesVendorClass(t *testing.T) {
+	options.RegisterVendorSubOption("AcmePhone", mustTypedOption(t, 1, options.OptionTypeIP, nil))
+	options.RegisterVendorSubOption("AcmePhone", mustTypedOption(t, 2, options.OptionTypeString, nil))
+
+	m := NewDiscoverMessage()
+	m.SetOption(options.NewVendorClassIdentifierOption("AcmePhone/9.2"))
+	m.SetOption(options.NewVendorSpecificInformationOption(
+		mustTypedOption(t, 1, options.OptionTypeIP, net.IPv4(192, 0, 2, 80)),
+		mustTypedOption(t, 2, options.OptionTypeString, "firmware-9.2.bin"),
+	))
+	decoded, err := FromBytes(m.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.VendorClass() != "AcmePhone/9.2" {
+		t.Fatalf("vendor class = %q", decoded.VendorClass())
+	}
+	vendor, ok := decoded.VendorSpecificInformation()
+	if !ok || len(vendor.SubOptions) != 2 {
+		t.Fatalf("vendor information = %v", vendor)
+	}
+	if got := vendor.SubOptions.Get(2).(options.TypedOption).Value; got != "firmware-9.2.bin" {
+		t.Fatalf("sub-option 2 = %v", got)
+	}
+}
+
+func TestClientArchitectureSelectsBootFile(t *testing.T) {
+	files := map[string]string{"bios": "pxelinux.0", "x64": "grubx64.efi", "arm64": "grubaa64.efi"}
+	tests := []struct {
+		architecture options.ClientArchitecture
+		want         string
+	}{
+		{options.ArchitectureX86BIOS, "pxelinux.0"},
+		{options.ArchitectureX64UEFI, "grubx64.efi"},
+		{options.ArchitectureEBC, "grubx64.efi"},
+		{options.ArchitectureARM64UEFI, "grubaa64.efi"},
+	}
+	for _, tt := range tests {
+		discover := NewDiscoverMessage()
+		discover.SetOption(options.NewClientSystemArchitectureOption(tt.architecture))
+		discover, err := FromBytes(discover.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		architecture, ok := discover.ClientArchitecture()
+		if !ok || architecture != tt.architecture {
+			t.Fatalf("architecture = %v, %v", architecture, ok)
+		}
+		var file string
+		switch {
+		case architecture.BIOS():
+			file = files["bios"]
+		case architecture.UEFIX64():
+			file = files["x64"]
+		case architecture.ARM64():
+			file = files["arm64"]
+		}
+		if file != tt.want {
+			t.Errorf("%s: file = %q, want %q", architecture, file, tt.want)
+		}
+
+		offer := NewOfferMessage(discover, "192.0.2.10")
+		offer.SetBootFile(net.ParseIP("192.0.2.5"), file)
+		decoded, err := FromBytes(offer.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := decoded.GetOption(options.OptionCodeBootFileName).(options.BootFileNameOption)
+		if decoded.BootFileName != file || name.BootFileName != file || !decoded.ServerIPAddr.Equal(net.ParseIP("192.0.2.5")) {
+			t.Errorf("%s: siaddr %s file %q option %q", architecture, decoded.ServerIPAddr, decoded.BootFileName, name.BootFileName)
+		}
+	}
+}
+
+func TestMessageClassAccessors(t *testing.T) {
+	m := NewDiscoverMessage()
+	m.SetOption(options.NewUserClassOption("kiosk"))
+	m.SetOption(options.NewVIVendorClassOption(4491, "docsis3.0"))
+	m.SetOption(options.NewVIVendorSpecificInformationOption(4491, mustTypedOption(t, 2, options.OptionTypeString, "CM")))
+	decoded, err := FromBytes(m.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.HasUserClass("kiosk") || decoded.HasUserClass("lab") {
+		t.Fatal("user class not matched")
+	}
+	if !decoded.HasVIVendorClass(4491, "docsis3.0") {
+		t.Fatal("vendor class not matched")
+	}
+	vendor, ok := decoded.VIVendorSpecificInformation()
+	if !ok || len(vendor.SubOptions(4491)) != 1 {
+		t.Fatalf("vendor-specific information = %v", vendor)
+	}
+}
+
+func TestEffectiveLinkAddressPrecedence(t *testing.T) {
+	base := func() *Message {
+		m := NewDiscoverMessage()
+		m.InterfaceAddr = net.ParseIP("10.0.0.1")
+		return m
+	}
+	relayed := func(m *Message) *Message {
+		m.GatewayIPAddr = net.ParseIP("192.0.2.1")
+		return m
+	}
+	linkSelected := func(m *Message) *Message {
+		m.SetOption(options.NewRelayAgentInformationOption(options.NewLinkSelectionSubOption("198.51.100.0")))
+		return m
+	}
+	subnetSelected := func(m *Message) *Message {
+		m.SetOption(options.NewSubnetSelectionOption("203.0.113.0"))
+		return m
+	}
+	tests := []struct {
+		name string
+		m    *Message
+		want string
+	}{
+		{"interface", base(), "10.0.0.1"},
+		{"giaddr", relayed(base()), "192.0.2.1"},
+		{"link selection", linkSelected(relayed(base())), "198.51.100.0"},
+		{"subnet selection", subnetSelected(linkSelected(relayed(base()))), "203.0.113.0"},
+		{"unknown", NewDiscoverMessage(), "<nil>"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.EffectiveLinkAddress().String(); got != tt.want {
+			t.Errorf("%s: link address = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEncryptedDNSOptionIsSplit(t *testing.T) {
+	var resolvers []options.DNRInstance
+	for i := 0; i < 6; i++ {
+		resolvers = append(resolvers, options.NewDNRInstance(uint16(i+1), fmt.Sprintf("resolver-%d.doh.example.net", i)).
+			WithAddresses(fmt.Sprintf("192.0.2.%d", i+1)).
+			WithALPN("h2", "h3").
+			WithDoHPath("/dns-query{?dns}"))
+	}
+	option := options.NewEncryptedDNSOption(resolvers...)
+	if len(option.Encode()) <= 255 {
+		t.Fatalf("option is only %d bytes", len(option.Encode()))
+	}
+	m := NewAckMessage(NewRequestMessage(), "192.0.2.10")
+	m.SetOption(option)
+	decoded, err := FromBytes(m.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := decoded.GetOption(options.OptionCodeEncryptedDNS).(options.EncryptedDNSOption)
+	if !ok || len(got.Resolvers) != 6 || got.Resolvers[5].ADN != "resolver-5.doh.example.net" {
+		t.Fatalf("decoded %v", decoded.GetOption(options.OptionCodeEncryptedDNS))
+	}
+}
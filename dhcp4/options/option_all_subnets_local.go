@@ -0,0 +1,63 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option27 All Subnets are Local Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-5.2
+// This option specifies whether or not the client may assume that all
+// subnets of the IP network to which the client is connected use the
+// same MTU as the subnet of that network to which the client is directly
+// connected. A value of 1 indicates that all subnets share the same MTU.
+// A value of 0 means that the client should assume that some subnets of
+// the directly connected network may have smaller MTUs. The code for
+// this option is 27, and its length is 1.
+//
+//	 Code   Len   Value
+//	+-----+-----+-----+
+//	| 27  |  1  | 0/1 |
+//	+-----+-----+-----+
+type AllSubnetsLocalOption struct {
+	Local bool `json:"local"`
+}
+
+func NewAllSubnetsLocalOption(enabled bool) Option {
+	return AllSubnetsLocalOption{
+		Local: enabled,
+	}
+}
+
+func (o AllSubnetsLocalOption) Code() OptionCode {
+	return OptionCodeAllSubnetsLocal
+}
+
+func (o AllSubnetsLocalOption) Encode() []byte {
+	return boolToBytes(o.Local)
+}
+
+func (o AllSubnetsLocalOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o AllSubnetsLocalOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	value, err := bytesToBool(b)
+	if err != nil {
+		return o, err
+	}
+	o.Local = value
+	return o, nil
+}
+
+func (o AllSubnetsLocalOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" All Subnets Local: %t", o.Local))
+	return buf.String()
+}
@@ -0,0 +1,55 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option35 ARP Cache Timeout Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-6.2
+// This option specifies the timeout in seconds for ARP cache entries.
+// The time is specified as a 32-bit unsigned integer. The code for this
+// option is 35, and its length is 4.
+//
+//	 Code   Len   ARP Cache Timeout
+//	+-----+-----+-----+-----+-----+-----+
+//	| 35  |  4  | t1  | t2  | t3  | t4  |
+//	+-----+-----+-----+-----+-----+-----+
+type ARPCacheTimeoutOption struct {
+	Timeout uint32 `json:"timeout"`
+}
+
+func NewARPCacheTimeoutOption(timeout uint32) Option {
+	return ARPCacheTimeoutOption{
+		Timeout: timeout,
+	}
+}
+
+func (o ARPCacheTimeoutOption) Code() OptionCode {
+	return OptionCodeARPCacheTimeout
+}
+
+func (o ARPCacheTimeoutOption) Encode() []byte {
+	return Uint32ToBytes(o.Timeout)
+}
+
+func (o ARPCacheTimeoutOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ARPCacheTimeoutOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.Timeout = BytesToUint32(b)
+	return o, nil
+}
+
+func (o ARPCacheTimeoutOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" ARP Cache Timeout: %d", o.Timeout))
+	return buf.String()
+}
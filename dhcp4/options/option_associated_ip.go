@@ -0,0 +1,63 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// Option92 Associated IP
+// https://www.rfc-editor.org/rfc/rfc4388#section-6.1
+// This option is returned in a DHCPLEASEACTIVE reply to a leasequery by
+// hardware address or client identifier when the client holds more than
+// one lease.  It lists every address bound to the client.  Its length is
+// a non-zero multiple of 4.
+//
+//	 Code   Len         Address 1             Address 2
+//	+-----+-----+-----+-----+-----+-----+-----+-----+--
+//	|  92 |  n  |  a1 |  a2 |  a3 |  a4 |  a1 |  a2 |  ...
+//	+-----+-----+-----+-----+-----+-----+-----+-----+--
+type AssociatedIPOption struct {
+	Addresses []net.IP
+}
+
+func NewAssociatedIPOption(addresses []string) Option {
+	o := AssociatedIPOption{}
+	for _, address := range addresses {
+		o.Addresses = append(o.Addresses, net.ParseIP(address))
+	}
+	return o
+}
+
+func (o AssociatedIPOption) Code() OptionCode {
+	return OptionCodeAssociatedIP
+}
+
+func (o AssociatedIPOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, address := range o.Addresses {
+		buf.Write(address.To4())
+	}
+	return buf.Bytes()
+}
+
+func (o AssociatedIPOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o AssociatedIPOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 4); err != nil {
+		return o, err
+	}
+	o.Addresses = decodeIPs(b)
+	return o, nil
+}
+
+func (o AssociatedIPOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Associated IPs: %v", o.Addresses))
+	return buf.String()
+}
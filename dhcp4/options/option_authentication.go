@@ -0,0 +1,199 @@
+package options
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+)
+
+// Authentication protocols, algorithms and replay detection methods
+// (RFC 3118 section 2).
+const (
+	AuthenticationProtocolConfigurationToken uint8 = 0
+	AuthenticationProtocolDelayed            uint8 = 1
+	AuthenticationProtocolForcerenewNonce    uint8 = 3 // https://www.rfc-editor.org/rfc/rfc6704#section-3.1.1
+
+	AuthenticationAlgorithmHMACMD5 uint8 = 1
+
+	ReplayDetectionMonotonicCounter uint8 = 0
+)
+
+// DelayedAuthenticationMACLength is the length of the HMAC-MD5 carried
+// by delayed authentication.
+const DelayedAuthenticationMACLength = 16
+
+// Types of the authentication information of the forcerenew nonce
+// protocol. The value that follows the type is 16 octets long.
+const (
+	ForcerenewNonceValue  uint8 = 1
+	ForcerenewNonceDigest uint8 = 2
+)
+
+// Option90 Authentication
+// https://www.rfc-editor.org/rfc/rfc3118#section-2
+// The authentication option carries the protocol used to authenticate
+// the message, a replay detection value and the authentication
+// information itself.  For delayed authentication the information is a
+// 32-bit secret ID followed by an HMAC-MD5 of the message, and a client
+// sends the option without information in DHCPDISCOVER to ask for
+// authentication.
+//
+//	0                   1                   2                   3
+//	0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|     Code      |    Length     |  Protocol     |   Algorithm   |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|     RDM       | Replay Detection (64 bits)                    |
+//	+-+-+-+-+-+-+-+-+                                               +
+//	|                                                               |
+//	+               +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|               |  Authentication Information                   |
+//	+-+-+-+-+-+-+-+-+                                               +
+//	|                                                               |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+type AuthenticationOption struct {
+	Protocol        uint8  `json:"protocol"`
+	Algorithm       uint8  `json:"algorithm"`
+	RDM             uint8  `json:"rdm"`
+	ReplayDetection uint64 `json:"replay_detection"`
+	Information     []byte `json:"information,omitempty"`
+}
+
+// NewDelayedAuthenticationOption returns a delayed authentication option
+// for secretID with an all-zero MAC, to be filled in once the message is
+// encoded.
+func NewDelayedAuthenticationOption(replayDetection uint64, secretID uint32) Option {
+	information := append(Uint32ToBytes(secretID), make([]byte, DelayedAuthenticationMACLength)...)
+	return AuthenticationOption{
+		Protocol:        AuthenticationProtocolDelayed,
+		Algorithm:       AuthenticationAlgorithmHMACMD5,
+		RDM:             ReplayDetectionMonotonicCounter,
+		ReplayDetection: replayDetection,
+		Information:     information,
+	}
+}
+
+// NewDelayedAuthenticationRequestOption returns the option a client
+// sends in DHCPDISCOVER to ask for delayed authentication.
+func NewDelayedAuthenticationRequestOption(replayDetection uint64) Option {
+	return AuthenticationOption{
+		Protocol:        AuthenticationProtocolDelayed,
+		Algorithm:       AuthenticationAlgorithmHMACMD5,
+		RDM:             ReplayDetectionMonotonicCounter,
+		ReplayDetection: replayDetection,
+	}
+}
+
+// NewForcerenewNonceOption returns the option a server sends in DHCPACK
+// to give the client the nonce for authenticating DHCPFORCERENEW.
+func NewForcerenewNonceOption(replayDetection uint64, nonce []byte) Option {
+	return AuthenticationOption{
+		Protocol:        AuthenticationProtocolForcerenewNonce,
+		Algorithm:       AuthenticationAlgorithmHMACMD5,
+		RDM:             ReplayDetectionMonotonicCounter,
+		ReplayDetection: replayDetection,
+		Information:     append([]byte{ForcerenewNonceValue}, nonce...),
+	}
+}
+
+// NewForcerenewDigestOption returns the option carried by
+// DHCPFORCERENEW with an all-zero HMAC-MD5, to be filled in once the
+// message is encoded.
+func NewForcerenewDigestOption(replayDetection uint64) Option {
+	return AuthenticationOption{
+		Protocol:        AuthenticationProtocolForcerenewNonce,
+		Algorithm:       AuthenticationAlgorithmHMACMD5,
+		RDM:             ReplayDetectionMonotonicCounter,
+		ReplayDetection: replayDetection,
+		Information:     append([]byte{ForcerenewNonceDigest}, make([]byte, DelayedAuthenticationMACLength)...),
+	}
+}
+
+func (o AuthenticationOption) Code() OptionCode {
+	return OptionCodeAuthentication
+}
+
+func (o AuthenticationOption) Encode() []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(o.Protocol)
+	buf.WriteByte(o.Algorithm)
+	buf.WriteByte(o.RDM)
+	buf.Write(Uint32ToBytes(uint32(o.ReplayDetection >> 32)))
+	buf.Write(Uint32ToBytes(uint32(o.ReplayDetection)))
+	buf.Write(o.Information)
+	return buf.Bytes()
+}
+
+func (o AuthenticationOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o AuthenticationOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 11); err != nil {
+		return o, err
+	}
+	o.Protocol, o.Algorithm, o.RDM = b[0], b[1], b[2]
+	o.ReplayDetection = uint64(BytesToUint32(b[3:]))<<32 | uint64(BytesToUint32(b[7:]))
+	o.Information = nil
+	if len(b) > 11 {
+		o.Information = b[11:]
+	}
+	return o, nil
+}
+
+// Delayed reports whether the option uses delayed authentication with
+// HMAC-MD5.
+func (o AuthenticationOption) Delayed() bool {
+	return o.Protocol == AuthenticationProtocolDelayed && o.Algorithm == AuthenticationAlgorithmHMACMD5
+}
+
+// SecretID returns the delayed authentication secret ID, if the option
+// carries one.
+func (o AuthenticationOption) SecretID() (uint32, bool) {
+	if !o.Delayed() || len(o.Information) != 4+DelayedAuthenticationMACLength {
+		return 0, false
+	}
+	return BytesToUint32(o.Information), true
+}
+
+// MAC returns the delayed authentication HMAC-MD5, or nil.
+func (o AuthenticationOption) MAC() []byte {
+	if _, ok := o.SecretID(); !ok {
+		return nil
+	}
+	return o.Information[4:]
+}
+
+// forcerenewValue returns the 16-octet value of the forcerenew nonce
+// information of type t.
+func (o AuthenticationOption) forcerenewValue(t uint8) ([]byte, bool) {
+	if o.Protocol != AuthenticationProtocolForcerenewNonce || o.Algorithm != AuthenticationAlgorithmHMACMD5 {
+		return nil, false
+	}
+	if len(o.Information) != 1+DelayedAuthenticationMACLength || o.Information[0] != t {
+		return nil, false
+	}
+	return o.Information[1:], true
+}
+
+// ForcerenewNonce returns the nonce a server sent in DHCPACK.
+func (o AuthenticationOption) ForcerenewNonce() ([]byte, bool) {
+	return o.forcerenewValue(ForcerenewNonceValue)
+}
+
+// ForcerenewDigest returns the HMAC-MD5 carried by DHCPFORCERENEW.
+func (o AuthenticationOption) ForcerenewDigest() ([]byte, bool) {
+	return o.forcerenewValue(ForcerenewNonceDigest)
+}
+
+func (o AuthenticationOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Protocol: %d, Algorithm: %d, RDM: %d, Replay Detection: %d", o.Protocol, o.Algorithm, o.RDM, o.ReplayDetection))
+	if len(o.Information) > 0 {
+		buf.WriteString(fmt.Sprintf(", Information: %s", hex.EncodeToString(o.Information)))
+	}
+	return buf.String()
+}
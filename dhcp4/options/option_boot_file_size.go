@@ -0,0 +1,55 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option13 Boot File Size Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-3.15
+// This option specifies the length in 512-octet blocks of the default
+// boot image for the client. The file length is specified as an unsigned
+// 16-bit integer. The code for this option is 13, and its length is 2.
+//
+//	 Code   Len   Boot File Size
+//	+-----+-----+-----+-----+
+//	| 13  |  2  | l1  | l2  |
+//	+-----+-----+-----+-----+
+type BootFileSizeOption struct {
+	BootFileSize uint16 `json:"boot_file_size"`
+}
+
+func NewBootFileSizeOption(blocks uint16) Option {
+	return BootFileSizeOption{
+		BootFileSize: blocks,
+	}
+}
+
+func (o BootFileSizeOption) Code() OptionCode {
+	return OptionCodeBootFileSize
+}
+
+func (o BootFileSizeOption) Encode() []byte {
+	return Uint16ToBytes(o.BootFileSize)
+}
+
+func (o BootFileSizeOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o BootFileSizeOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 2); err != nil {
+		return o, err
+	}
+	o.BootFileSize = BytesToUint16(b)
+	return o, nil
+}
+
+func (o BootFileSizeOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Boot File Size: %d", o.BootFileSize))
+	return buf.String()
+}
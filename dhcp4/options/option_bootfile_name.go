@@ -0,0 +1,56 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option67 Bootfile name
+// https://www.rfc-editor.org/rfc/rfc2132#section-9.5
+// This option is used to identify a bootfile when the 'file' field in
+// the DHCP header has been used for DHCP options.
+//
+// The code for this option is 67, and its minimum length is 1.
+//
+//	 Code  Len   Bootfile name
+//	+-----+-----+-----+-----+-----+---
+//	| 67  |  n  |  c1 |  c2 |  c3 | ...
+//	+-----+-----+-----+-----+-----+---
+type BootFileNameOption struct {
+	BootFileName string `json:"bootfile_name"`
+}
+
+func NewBootFileNameOption(bootFileName string) Option {
+	return BootFileNameOption{
+		BootFileName: bootFileName,
+	}
+}
+
+func (o BootFileNameOption) Code() OptionCode {
+	return OptionCodeBootFileName
+}
+
+func (o BootFileNameOption) Encode() []byte {
+	return []byte(o.BootFileName)
+}
+
+func (o BootFileNameOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o BootFileNameOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.BootFileName = string(b)
+	return o, nil
+}
+
+func (o BootFileNameOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Bootfile: %s", o.BootFileName))
+	return buf.String()
+}
@@ -37,9 +37,17 @@ func (o BroadcastAddressOption) Encode() []byte {
 	return o.BroadcastAddress.To4()
 }
 
-func (b BroadcastAddressOption) Decode(d []byte) Option {
-	b.BroadcastAddress = d
-	return b
+func (o BroadcastAddressOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o BroadcastAddressOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.BroadcastAddress = net.IP(b)
+	return o, nil
 }
 
 func (o BroadcastAddressOption) String() string {
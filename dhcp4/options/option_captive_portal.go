@@ -0,0 +1,72 @@
+package options
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// CaptivePortalUnrestricted is the URI a server sends to say the network
+// has no captive portal (RFC 8910 section 2).
+const CaptivePortalUnrestricted = "urn:ietf:params:capport:unrestricted"
+
+// Option114 DHCP Captive-Portal
+// https://www.rfc-editor.org/rfc/rfc8910#section-2.1
+// This option gives the URI of the captive portal API (RFC 8908) the
+// client must use before it gets full network access.  The URI is an
+// absolute URI and should use https.
+//
+//	 Code   Len          URI (variable length)         ...
+//	+-----+-----+-----+-----+-----+-----+-----+-----+---
+//	| 114 |  n  |  u1 |  u2 |  u3 |  u4 |  u5 |  u6 | ...
+//	+-----+-----+-----+-----+-----+-----+-----+-----+---
+type CaptivePortalOption struct {
+	URI string `json:"uri"`
+}
+
+func NewCaptivePortalOption(uri string) Option {
+	return CaptivePortalOption{URI: uri}
+}
+
+func (o CaptivePortalOption) Code() OptionCode {
+	return OptionCodeCaptivePortal
+}
+
+// Unrestricted reports whether the option says there is no captive
+// portal.
+func (o CaptivePortalOption) Unrestricted() bool {
+	return o.URI == CaptivePortalUnrestricted
+}
+
+func (o CaptivePortalOption) Encode() []byte {
+	return []byte(o.URI)
+}
+
+func (o CaptivePortalOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o CaptivePortalOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	uri, err := url.Parse(string(b))
+	if err != nil {
+		return o, err
+	}
+	if !uri.IsAbs() {
+		return o, errors.New("captive portal URI is not absolute")
+	}
+	o.URI = string(b)
+	return o, nil
+}
+
+func (o CaptivePortalOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Captive Portal: %s", o.URI))
+	return buf.String()
+}
@@ -0,0 +1,125 @@
+package options
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Option121 Classless Static Route Option
+// https://www.rfc-editor.org/rfc/rfc3442
+// This option specifies a list of classless static routes that the
+// client should install in its routing cache.  If multiple routes to the
+// same destination are specified, they are listed in descending order of
+// priority.
+//
+// Each destination is encoded as a descriptor: the subnet mask width
+// followed by only the significant octets of the subnet number, so a /24
+// destination takes three octets and the default route none.
+//
+//	 Code Len Destination 1    Router 1
+//	+-----+---+----+-----+----+----+----+----+----+
+//	| 121 | n | d1 | ... | dN | r1 | r2 | r3 | r4 |
+//	+-----+---+----+-----+----+----+----+----+----+
+//	 Destination 2       Router 2
+//	+----+-----+----+----+----+----+----+
+//	| d1 | ... | dN | r1 | r2 | r3 | r4 |
+//	+----+-----+----+----+----+----+----+
+//
+// Microsoft clients use the same format under the site-specific code 249.
+type ClasslessStaticRouteOption struct {
+	code   OptionCode
+	Routes []ClasslessRoute
+}
+
+// ClasslessRoute is a destination network and the router to reach it.
+type ClasslessRoute struct {
+	Destination net.IPNet
+	Router      net.IP
+}
+
+// NewClasslessRoute returns the route to destination through router.
+func NewClasslessRoute(destination net.IPNet, router net.IP) ClasslessRoute {
+	return ClasslessRoute{Destination: destination, Router: router}
+}
+
+func NewClasslessStaticRouteOption(routes []ClasslessRoute) Option {
+	return ClasslessStaticRouteOption{
+		code:   OptionCodeClasslessStaticRoute,
+		Routes: routes,
+	}
+}
+
+// NewMicrosoftClasslessStaticRouteOption returns the routes under the
+// code 249 alias used by older Windows clients.
+func NewMicrosoftClasslessStaticRouteOption(routes []ClasslessRoute) Option {
+	return ClasslessStaticRouteOption{
+		code:   OptionCodeMicrosoftClasslessStaticRoute,
+		Routes: routes,
+	}
+}
+
+func (o ClasslessStaticRouteOption) Code() OptionCode {
+	if o.code == 0 {
+		return OptionCodeClasslessStaticRoute
+	}
+	return o.code
+}
+
+func (o ClasslessStaticRouteOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, route := range o.Routes {
+		width, _ := route.Destination.Mask.Size()
+		destination := route.Destination.IP.Mask(route.Destination.Mask).To4()
+		if destination == nil || width > 32 {
+			continue
+		}
+		buf.WriteByte(byte(width))
+		buf.Write(destination[:(width+7)/8])
+		buf.Write(route.Router.To4())
+	}
+	return buf.Bytes()
+}
+
+func (o ClasslessStaticRouteOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ClasslessStaticRouteOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 5); err != nil {
+		return o, err
+	}
+	o.Routes = nil
+	for len(b) > 0 {
+		width := int(b[0])
+		if width > 32 {
+			return o, fmt.Errorf("invalid destination mask width %d", width)
+		}
+		significant := (width + 7) / 8
+		if len(b) < 1+significant+4 {
+			return o, errors.New("truncated classless route")
+		}
+		destination := make(net.IP, net.IPv4len)
+		copy(destination, b[1:1+significant])
+		mask := net.CIDRMask(width, 32)
+		o.Routes = append(o.Routes, ClasslessRoute{
+			Destination: net.IPNet{IP: destination.Mask(mask), Mask: mask},
+			Router:      net.IP(b[1+significant : 1+significant+4]),
+		})
+		b = b[1+significant+4:]
+	}
+	return o, nil
+}
+
+func (o ClasslessStaticRouteOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" Classless Static Routes:")
+	for _, route := range o.Routes {
+		buf.WriteString(fmt.Sprintf(" %s via %s", route.Destination.String(), route.Router))
+	}
+	return buf.String()
+}
@@ -0,0 +1,204 @@
+package options
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Flags of the client FQDN option.
+const (
+	FQDNFlagS uint8 = 0x01 // server performs the A RR update
+	FQDNFlagO uint8 = 0x02 // server overrode the client's S bit
+	FQDNFlagE uint8 = 0x04 // domain name uses canonical wire format
+	FQDNFlagN uint8 = 0x08 // server performs no DNS updates
+)
+
+// Option81 Client Fully Qualified Domain Name
+// https://www.rfc-editor.org/rfc/rfc4702
+// This option lets a client tell the server its fully qualified domain
+// name and agree on who updates the A and PTR resource records.
+//
+//	 Code   Len    Flags  RCODE1 RCODE2   Domain Name
+//	+------+------+------+------+------+------+--
+//	|  81  |   n  |      |      |      |       ...
+//	+------+------+------+------+------+------+--
+//
+//	 0 1 2 3 4 5 6 7
+//	+-+-+-+-+-+-+-+-+
+//	|  MBZ  |N|E|O|S|
+//	+-+-+-+-+-+-+-+-+
+//
+// With the E bit set the name is in DNS wire format; a name without a
+// terminating zero-length label is partial.  Without it the name is the
+// deprecated ASCII encoding.  Fully qualified names are kept with a
+// trailing dot so both forms round-trip.
+type ClientFQDNOption struct {
+	Flags      uint8  `json:"flags"`
+	RCode1     uint8  `json:"rcode1"`
+	RCode2     uint8  `json:"rcode2"`
+	DomainName string `json:"domain_name"`
+}
+
+// NewClientFQDNOption returns the option a client sends for name using
+// canonical encoding. serverUpdate asks the server to update the A RR.
+// Check the result with Validate; an invalid name is not encoded.
+func NewClientFQDNOption(name string, serverUpdate bool) Option {
+	o := ClientFQDNOption{Flags: FQDNFlagE, DomainName: name}
+	if serverUpdate {
+		o.Flags |= FQDNFlagS
+	}
+	return o
+}
+
+// Reply returns the option a server sends in answer to the client's
+// option. serverUpdates reports whether the server performs the A RR
+// update; O is set when that overrides the client's S bit, and N when the
+// client asked for no server updates and the server agrees. RCODE1 and
+// RCODE2 are set to 255 as RFC 4702 section 2.2 requires.
+func (o ClientFQDNOption) Reply(name string, serverUpdates bool) ClientFQDNOption {
+	reply := ClientFQDNOption{
+		Flags:      o.Flags & FQDNFlagE,
+		RCode1:     255,
+		RCode2:     255,
+		DomainName: name,
+	}
+	if serverUpdates {
+		reply.Flags |= FQDNFlagS
+	}
+	if serverUpdates != o.ServerUpdate() {
+		reply.Flags |= FQDNFlagO
+	}
+	if !serverUpdates && o.NoServerUpdate() {
+		reply.Flags |= FQDNFlagN
+	}
+	return reply
+}
+
+// NoUpdateReply returns the option a server that performs no DNS
+// updates sends in answer to the client's option (RFC 4702 section 4).
+// N is set, and O when the client asked the server to update the A RR.
+func (o ClientFQDNOption) NoUpdateReply(name string) ClientFQDNOption {
+	reply := o.Reply(name, false)
+	reply.Flags |= FQDNFlagN
+	return reply
+}
+
+// ServerUpdate reports whether the S bit is set.
+func (o ClientFQDNOption) ServerUpdate() bool {
+	return o.Flags&FQDNFlagS != 0
+}
+
+// Override reports whether the O bit is set.
+func (o ClientFQDNOption) Override() bool {
+	return o.Flags&FQDNFlagO != 0
+}
+
+// Canonical reports whether the E bit is set.
+func (o ClientFQDNOption) Canonical() bool {
+	return o.Flags&FQDNFlagE != 0
+}
+
+// NoServerUpdate reports whether the N bit is set.
+func (o ClientFQDNOption) NoServerUpdate() bool {
+	return o.Flags&FQDNFlagN != 0
+}
+
+func (o ClientFQDNOption) Code() OptionCode {
+	return OptionCodeClientFullyQualifiedDomainName
+}
+
+// Validate reports a canonical name with an empty label, a label longer
+// than 63 octets or more than 255 octets in all. Encode returns nothing
+// for such a name.
+func (o ClientFQDNOption) Validate() error {
+	if !o.Canonical() {
+		return nil
+	}
+	name := strings.TrimSuffix(o.DomainName, ".")
+	if name == "" {
+		return nil
+	}
+	if !validDomainLabels(strings.Split(name, ".")) {
+		return fmt.Errorf("invalid domain name %q", o.DomainName)
+	}
+	return nil
+}
+
+func (o ClientFQDNOption) Encode() []byte {
+	if o.Validate() != nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(o.Flags)
+	buf.WriteByte(o.RCode1)
+	buf.WriteByte(o.RCode2)
+	if !o.Canonical() {
+		buf.WriteString(o.DomainName)
+		return buf.Bytes()
+	}
+	name := strings.TrimSuffix(o.DomainName, ".")
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			buf.WriteByte(byte(len(label)))
+			buf.WriteString(label)
+		}
+	}
+	if strings.HasSuffix(o.DomainName, ".") {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+func (o ClientFQDNOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ClientFQDNOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 3); err != nil {
+		return o, err
+	}
+	if b[0]&0xf0 != 0 {
+		return o, fmt.Errorf("reserved flag bits set in %#x", b[0])
+	}
+	if b[0]&FQDNFlagN != 0 && b[0]&FQDNFlagS != 0 {
+		return o, errors.New("both N and S flags set")
+	}
+	o.Flags, o.RCode1, o.RCode2 = b[0], b[1], b[2]
+	if !o.Canonical() {
+		o.DomainName = string(b[3:])
+		return o, nil
+	}
+	var labels []string
+	qualified := false
+	for data := b[3:]; len(data) > 0; {
+		size := int(data[0])
+		if size == 0 {
+			if len(data) != 1 {
+				return o, errors.New("data after terminating label")
+			}
+			qualified = true
+			break
+		}
+		if size > 63 || len(data) < 1+size {
+			return o, errors.New("invalid domain label")
+		}
+		labels = append(labels, string(data[1:1+size]))
+		data = data[1+size:]
+	}
+	o.DomainName = strings.Join(labels, ".")
+	if qualified {
+		o.DomainName += "."
+	}
+	return o, nil
+}
+
+func (o ClientFQDNOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Client FQDN: %s Flags: %#x RCODE1: %d RCODE2: %d", o.DomainName, o.Flags, o.RCode1, o.RCode2))
+	return buf.String()
+}
@@ -57,13 +57,21 @@ func (o ClientIdentifierOption) Encode() []byte {
 }
 
 func (o ClientIdentifierOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ClientIdentifierOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 2); err != nil {
+		return o, err
+	}
 	if b[0] == 0x01 { // FIXME: only 0x01 is supported
 		o.Type = b[0]
 		o.ClientIdentifier = b[1:]
 	} else {
 		o.ClientIdentifier = b
 	}
-	return o
+	return o, nil
 }
 
 func (o ClientIdentifierOption) String() string {
@@ -0,0 +1,53 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option91 Client Last Transaction Time
+// https://www.rfc-editor.org/rfc/rfc4388#section-6.1
+// This option is returned in a DHCPLEASEACTIVE reply to a leasequery.
+// It gives the number of seconds since the server last heard from the
+// client whose lease is reported.
+//
+//	 Code   Len   Seconds in the past
+//	+-----+-----+-----+-----+-----+-----+
+//	|  91 |  4  |  t1 |  t2 |  t3 |  t4 |
+//	+-----+-----+-----+-----+-----+-----+
+type ClientLastTransactionTimeOption struct {
+	Seconds uint32 `json:"seconds"`
+}
+
+func NewClientLastTransactionTimeOption(seconds uint32) Option {
+	return ClientLastTransactionTimeOption{Seconds: seconds}
+}
+
+func (o ClientLastTransactionTimeOption) Code() OptionCode {
+	return OptionCodeClientLastTransactionTime
+}
+
+func (o ClientLastTransactionTimeOption) Encode() []byte {
+	return Uint32ToBytes(o.Seconds)
+}
+
+func (o ClientLastTransactionTimeOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ClientLastTransactionTimeOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.Seconds = BytesToUint32(b)
+	return o, nil
+}
+
+func (o ClientLastTransactionTimeOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Client Last Transaction Time: %ds", o.Seconds))
+	return buf.String()
+}
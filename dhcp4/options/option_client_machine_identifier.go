@@ -0,0 +1,72 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// MachineIdentifierTypeGUID is the only identifier type defined for the
+// client machine identifier option.
+const MachineIdentifierTypeGUID = 0
+
+// Option97 Client Machine Identifier
+// https://www.rfc-editor.org/rfc/rfc4578#section-2.3
+// This option carries the client's 16-octet machine UUID (GUID),
+// preceded by a type octet of 0. Its length is 17.
+//
+//	 Code  Len  Type  Machine Identifier
+//	+----+-----+----+-----+ . . . +-----+
+//	| 97 |  n  | t  |     |       |     |
+//	+----+-----+----+-----+ . . . +-----+
+type ClientMachineIdentifierOption struct {
+	Type uint8  `json:"type"`
+	UUID []byte `json:"uuid"`
+}
+
+// NewClientMachineIdentifierOption returns a GUID machine identifier.
+// uuid must be 16 bytes long.
+func NewClientMachineIdentifierOption(uuid []byte) Option {
+	return ClientMachineIdentifierOption{
+		Type: MachineIdentifierTypeGUID,
+		UUID: uuid,
+	}
+}
+
+func (o ClientMachineIdentifierOption) Code() OptionCode {
+	return OptionCodeClientMachineIdentifier
+}
+
+func (o ClientMachineIdentifierOption) Encode() []byte {
+	return append([]byte{o.Type}, o.UUID...)
+}
+
+func (o ClientMachineIdentifierOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ClientMachineIdentifierOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 17); err != nil {
+		return o, err
+	}
+	o.Type = b[0]
+	o.UUID = b[1:]
+	return o, nil
+}
+
+// UUIDString formats the identifier in the canonical 8-4-4-4-12 form.
+func (o ClientMachineIdentifierOption) UUIDString() string {
+	if len(o.UUID) != 16 {
+		return fmt.Sprintf("%x", o.UUID)
+	}
+	u := o.UUID
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+func (o ClientMachineIdentifierOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Type: %d, UUID: %s", o.Type, o.UUIDString()))
+	return buf.String()
+}
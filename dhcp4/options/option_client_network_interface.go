@@ -0,0 +1,65 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// NetworkInterfaceTypeUNDI is the only interface type defined for the
+// client network interface identifier option.
+const NetworkInterfaceTypeUNDI = 1
+
+// Option94 Client Network Interface Identifier
+// https://www.rfc-editor.org/rfc/rfc4578#section-2.2
+// This option gives the type and revision of the client's network
+// interface, which for PXE is the UNDI (Universal Network Driver
+// Interface) version. Its length is 3.
+//
+//	 Code  Len  Type  Major Minor
+//	+----+-----+----+-----+-----+
+//	| 94 |  3  | t1 |  M  |  m  |
+//	+----+-----+----+-----+-----+
+type ClientNetworkInterfaceOption struct {
+	Type  uint8 `json:"type"`
+	Major uint8 `json:"major"`
+	Minor uint8 `json:"minor"`
+}
+
+// NewClientNetworkInterfaceOption returns a UNDI interface identifier
+// for revision major.minor.
+func NewClientNetworkInterfaceOption(major, minor uint8) Option {
+	return ClientNetworkInterfaceOption{
+		Type:  NetworkInterfaceTypeUNDI,
+		Major: major,
+		Minor: minor,
+	}
+}
+
+func (o ClientNetworkInterfaceOption) Code() OptionCode {
+	return OptionCodeClientNetworkInterface
+}
+
+func (o ClientNetworkInterfaceOption) Encode() []byte {
+	return []byte{o.Type, o.Major, o.Minor}
+}
+
+func (o ClientNetworkInterfaceOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ClientNetworkInterfaceOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 3); err != nil {
+		return o, err
+	}
+	o.Type, o.Major, o.Minor = b[0], b[1], b[2]
+	return o, nil
+}
+
+func (o ClientNetworkInterfaceOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Type: %d, Revision: %d.%d", o.Type, o.Major, o.Minor))
+	return buf.String()
+}
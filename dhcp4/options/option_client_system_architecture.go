@@ -0,0 +1,150 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ClientArchitecture is a processor architecture type from the IANA
+// "Processor Architecture Types" registry (RFC 4578 section 2.1).
+type ClientArchitecture uint16
+
+const (
+	ArchitectureX86BIOS         ClientArchitecture = 0
+	ArchitectureNECPC98         ClientArchitecture = 1
+	ArchitectureItanium         ClientArchitecture = 2
+	ArchitectureDECAlpha        ClientArchitecture = 3
+	ArchitectureArcX86          ClientArchitecture = 4
+	ArchitectureIntelLean       ClientArchitecture = 5
+	ArchitectureX86UEFI         ClientArchitecture = 6
+	ArchitectureX64UEFI         ClientArchitecture = 7
+	ArchitectureXscale          ClientArchitecture = 8
+	ArchitectureEBC             ClientArchitecture = 9
+	ArchitectureARM32UEFI       ClientArchitecture = 10
+	ArchitectureARM64UEFI       ClientArchitecture = 11
+	ArchitectureX86UEFIHTTP     ClientArchitecture = 15
+	ArchitectureX64UEFIHTTP     ClientArchitecture = 16
+	ArchitectureEBCHTTP         ClientArchitecture = 17
+	ArchitectureARM32UEFIHTTP   ClientArchitecture = 18
+	ArchitectureARM64UEFIHTTP   ClientArchitecture = 19
+	ArchitectureX86BIOSHTTP     ClientArchitecture = 20
+	ArchitectureRISCV64UEFI     ClientArchitecture = 27
+	ArchitectureRISCV64UEFIHTTP ClientArchitecture = 28
+)
+
+var architectureNames = map[ClientArchitecture]string{
+	ArchitectureX86BIOS:         "x86 BIOS",
+	ArchitectureNECPC98:         "NEC/PC98",
+	ArchitectureItanium:         "Itanium",
+	ArchitectureDECAlpha:        "DEC Alpha",
+	ArchitectureArcX86:          "Arc x86",
+	ArchitectureIntelLean:       "Intel Lean Client",
+	ArchitectureX86UEFI:         "x86 UEFI",
+	ArchitectureX64UEFI:         "x64 UEFI",
+	ArchitectureXscale:          "EFI Xscale",
+	ArchitectureEBC:             "EBC",
+	ArchitectureARM32UEFI:       "ARM 32-bit UEFI",
+	ArchitectureARM64UEFI:       "ARM 64-bit UEFI",
+	ArchitectureX86UEFIHTTP:     "x86 UEFI HTTP",
+	ArchitectureX64UEFIHTTP:     "x64 UEFI HTTP",
+	ArchitectureEBCHTTP:         "EBC HTTP",
+	ArchitectureARM32UEFIHTTP:   "ARM 32-bit UEFI HTTP",
+	ArchitectureARM64UEFIHTTP:   "ARM 64-bit UEFI HTTP",
+	ArchitectureX86BIOSHTTP:     "x86 BIOS HTTP",
+	ArchitectureRISCV64UEFI:     "RISC-V 64-bit UEFI",
+	ArchitectureRISCV64UEFIHTTP: "RISC-V 64-bit UEFI HTTP",
+}
+
+func (a ClientArchitecture) String() string {
+	if name, ok := architectureNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unknown(%d)", uint16(a))
+}
+
+// BIOS reports whether a is a legacy x86 PC BIOS.
+func (a ClientArchitecture) BIOS() bool {
+	return a == ArchitectureX86BIOS || a == ArchitectureX86BIOSHTTP
+}
+
+// UEFIX64 reports whether a is a 64-bit x86 UEFI firmware. Type 9 (EBC)
+// is included because RFC 4578 originally assigned it to x86-64 and some
+// firmware still sends it.
+func (a ClientArchitecture) UEFIX64() bool {
+	switch a {
+	case ArchitectureX64UEFI, ArchitectureEBC, ArchitectureX64UEFIHTTP, ArchitectureEBCHTTP:
+		return true
+	}
+	return false
+}
+
+// ARM64 reports whether a is a 64-bit ARM UEFI firmware.
+func (a ClientArchitecture) ARM64() bool {
+	return a == ArchitectureARM64UEFI || a == ArchitectureARM64UEFIHTTP
+}
+
+// HTTP reports whether a boots over HTTP rather than TFTP.
+func (a ClientArchitecture) HTTP() bool {
+	switch a {
+	case ArchitectureX86UEFIHTTP, ArchitectureX64UEFIHTTP, ArchitectureEBCHTTP,
+		ArchitectureARM32UEFIHTTP, ArchitectureARM64UEFIHTTP, ArchitectureX86BIOSHTTP,
+		ArchitectureRISCV64UEFIHTTP:
+		return true
+	}
+	return false
+}
+
+// Option93 Client System Architecture Type
+// https://www.rfc-editor.org/rfc/rfc4578#section-2.1
+// This option lists the pre-boot architectures supported by the client,
+// as 16-bit types. Its length is a non-zero multiple of 2.
+//
+//	 Code  Len  16-bit Type
+//	+----+-----+-----+-----+
+//	| 93 |  n  | n1  | n2  |
+//	+----+-----+-----+-----+
+type ClientSystemArchitectureOption struct {
+	Architectures []ClientArchitecture `json:"architectures"`
+}
+
+func NewClientSystemArchitectureOption(architectures ...ClientArchitecture) Option {
+	return ClientSystemArchitectureOption{
+		Architectures: architectures,
+	}
+}
+
+func (o ClientSystemArchitectureOption) Code() OptionCode {
+	return OptionCodeClientSystemArchitecture
+}
+
+func (o ClientSystemArchitectureOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, architecture := range o.Architectures {
+		buf.Write(Uint16ToBytes(uint16(architecture)))
+	}
+	return buf.Bytes()
+}
+
+func (o ClientSystemArchitectureOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ClientSystemArchitectureOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 2); err != nil {
+		return o, err
+	}
+	o.Architectures = make([]ClientArchitecture, 0, len(b)/2)
+	for i := 0; i < len(b); i += 2 {
+		o.Architectures = append(o.Architectures, ClientArchitecture(BytesToUint16(b[i:])))
+	}
+	return o, nil
+}
+
+func (o ClientSystemArchitectureOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Architectures: %v", o.Architectures))
+	return buf.String()
+}
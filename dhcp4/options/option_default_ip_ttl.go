@@ -0,0 +1,59 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option23 Default IP Time-to-live
+// https://www.rfc-editor.org/rfc/rfc2132#section-4.2
+// This option specifies the default time-to-live that the client should
+// use on outgoing datagrams. The TTL is specified as an octet with a
+// value between 1 and 255. The code for this option is 23, and its
+// length is 1.
+//
+//	 Code   Len   Default IP TTL
+//	+-----+-----+-----+
+//	| 23  |  1  |  v  |
+//	+-----+-----+-----+
+type DefaultIPTimeToLiveOption struct {
+	TTL uint8 `json:"ttl"`
+}
+
+func NewDefaultIPTimeToLiveOption(ttl uint8) Option {
+	return DefaultIPTimeToLiveOption{
+		TTL: ttl,
+	}
+}
+
+func (o DefaultIPTimeToLiveOption) Code() OptionCode {
+	return OptionCodeDefaultIPTimeToLive
+}
+
+func (o DefaultIPTimeToLiveOption) Encode() []byte {
+	return Uint8ToBytes(o.TTL)
+}
+
+func (o DefaultIPTimeToLiveOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o DefaultIPTimeToLiveOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	if b[0] == 0 {
+		return o, fmt.Errorf("invalid time-to-live %d", b[0])
+	}
+	o.TTL = b[0]
+	return o, nil
+}
+
+func (o DefaultIPTimeToLiveOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Default IP TTL: %d", o.TTL))
+	return buf.String()
+}
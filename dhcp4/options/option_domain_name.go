@@ -33,8 +33,16 @@ func (o DomainNameOption) Encode() []byte {
 }
 
 func (o DomainNameOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o DomainNameOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
 	o.Domain = string(b)
-	return o
+	return o, nil
 }
 
 func (o DomainNameOption) String() string {
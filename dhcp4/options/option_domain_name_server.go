@@ -44,10 +44,16 @@ func (o DomainNameServerOption) Encode() []byte {
 }
 
 func (o DomainNameServerOption) Decode(b []byte) Option {
-	for i := 0; i < len(b); i += 4 {
-		o.DomainNameServers = append(o.DomainNameServers, b[i:i+4])
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o DomainNameServerOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 4); err != nil {
+		return o, err
 	}
-	return o
+	o.DomainNameServers = decodeIPs(b)
+	return o, nil
 }
 
 func (o DomainNameServerOption) String() string {
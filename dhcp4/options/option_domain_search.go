@@ -0,0 +1,163 @@
+package options
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Option119 Domain Search Option
+// https://www.rfc-editor.org/rfc/rfc3397
+// The domain search option specifies the search list the client uses
+// when resolving hostnames with DNS.  The names are encoded in DNS wire
+// format (RFC 1035 section 3.1) and may use the compression pointers of
+// RFC 1035 section 4.1.4, with offsets counted from the start of the
+// option data.  Long lists are split over several options as described
+// in RFC 3396.
+//
+//	 Code  Len         Searchstring
+//	+-----+-----+-----+-----+-----+-----+-----+---
+//	| 119 | Len |  s1 |  s2 |  s3 |  s4 |  s5 | ...
+//	+-----+-----+-----+-----+-----+-----+-----+---
+type DomainSearchOption struct {
+	Domains []string `json:"domains"`
+}
+
+func NewDomainSearchOption(domains []string) Option {
+	return DomainSearchOption{
+		Domains: domains,
+	}
+}
+
+func (o DomainSearchOption) Code() OptionCode {
+	return OptionCodeDomainSearch
+}
+
+// Encode leaves out domains with an empty label, a label longer than 63
+// octets or a name longer than 255 octets.
+func (o DomainSearchOption) Encode() []byte {
+	var buf bytes.Buffer
+	suffixes := make(map[string]int)
+	for _, domain := range o.Domains {
+		labels := strings.Split(strings.TrimSuffix(domain, "."), ".")
+		if len(labels) == 1 && labels[0] == "" {
+			labels = nil
+		}
+		if !validDomainLabels(labels) {
+			continue
+		}
+		compressed := false
+		for i := range labels {
+			suffix := strings.ToLower(strings.Join(labels[i:], "."))
+			if offset, ok := suffixes[suffix]; ok {
+				buf.Write(Uint16ToBytes(0xc000 | uint16(offset)))
+				compressed = true
+				break
+			}
+			if buf.Len() < 0x4000 {
+				suffixes[suffix] = buf.Len()
+			}
+			buf.WriteByte(byte(len(labels[i])))
+			buf.WriteString(labels[i])
+		}
+		if !compressed {
+			buf.WriteByte(0)
+		}
+	}
+	return buf.Bytes()
+}
+
+// validDomainLabels reports whether labels form a domain name that fits
+// DNS wire format (RFC 1035 section 2.3.4).
+func validDomainLabels(labels []string) bool {
+	length := 1
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 {
+			return false
+		}
+		length += 1 + len(label)
+	}
+	return length <= 255
+}
+
+func (o DomainSearchOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o DomainSearchOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.Domains = nil
+	for offset := 0; offset < len(b); {
+		domain, next, err := decodeDomainName(b, offset)
+		if err != nil {
+			return o, err
+		}
+		o.Domains = append(o.Domains, domain)
+		offset = next
+	}
+	return o, nil
+}
+
+// decodeDomainName reads the DNS wire format name at offset in data and
+// returns it with the offset following it. Compression pointers must
+// point strictly before every position already visited, which rules out
+// pointer loops.
+func decodeDomainName(data []byte, offset int) (string, int, error) {
+	var labels []string
+	next := -1
+	limit := offset
+	length := 0
+	for {
+		if offset >= len(data) {
+			return "", 0, errors.New("truncated domain name")
+		}
+		size := int(data[offset])
+		switch {
+		case size == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return strings.Join(labels, "."), next, nil
+		case size&0xc0 == 0xc0:
+			if offset+1 >= len(data) {
+				return "", 0, errors.New("truncated compression pointer")
+			}
+			pointer := int(BytesToUint16(data[offset:offset+2]) & 0x3fff)
+			if pointer >= limit {
+				return "", 0, fmt.Errorf("compression pointer %d does not point backwards", pointer)
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset, limit = pointer, pointer
+		case size&0xc0 != 0:
+			return "", 0, fmt.Errorf("invalid label type %#x", size&0xc0)
+		default:
+			if offset+1+size > len(data) {
+				return "", 0, errors.New("truncated domain label")
+			}
+			length += size + 1
+			if length > 255 {
+				return "", 0, errors.New("domain name too long")
+			}
+			label := string(data[offset+1 : offset+1+size])
+			if strings.Contains(label, ".") {
+				return "", 0, errors.New("domain label contains a dot")
+			}
+			labels = append(labels, label)
+			offset += 1 + size
+		}
+	}
+}
+
+func (o DomainSearchOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Domain Search: %s", strings.Join(o.Domains, " ")))
+	return buf.String()
+}
@@ -0,0 +1,525 @@
+package options
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// SvcParamKey identifies a service parameter of an encrypted DNS
+// resolver (RFC 9460 section 14.3.2).
+type SvcParamKey uint16
+
+const (
+	SvcParamMandatory     SvcParamKey = 0 // https://www.rfc-editor.org/rfc/rfc9460#section-8
+	SvcParamALPN          SvcParamKey = 1 // https://www.rfc-editor.org/rfc/rfc9460#section-7.1
+	SvcParamNoDefaultALPN SvcParamKey = 2
+	SvcParamPort          SvcParamKey = 3 // https://www.rfc-editor.org/rfc/rfc9460#section-7.2
+	SvcParamIPv4Hint      SvcParamKey = 4
+	SvcParamECH           SvcParamKey = 5
+	SvcParamIPv6Hint      SvcParamKey = 6
+	SvcParamDoHPath       SvcParamKey = 7 // https://www.rfc-editor.org/rfc/rfc9461#section-5
+)
+
+// SvcParam is a service parameter kept in its wire format.
+type SvcParam struct {
+	Key   SvcParamKey `json:"key"`
+	Value []byte      `json:"value"`
+}
+
+// SvcParams are the service parameters of an encrypted DNS resolver.
+// Port is zero when the default port of the protocol is used.
+type SvcParams struct {
+	Mandatory     []SvcParamKey `json:"mandatory,omitempty"`
+	ALPN          []string      `json:"alpn,omitempty"`
+	NoDefaultALPN bool          `json:"no_default_alpn,omitempty"`
+	Port          uint16        `json:"port,omitempty"`
+	DoHPath       string        `json:"dohpath,omitempty"`
+	// Other holds the parameters with any other key.
+	Other []SvcParam `json:"other,omitempty"`
+}
+
+func (p SvcParams) empty() bool {
+	return len(p.Mandatory) == 0 && len(p.ALPN) == 0 && !p.NoDefaultALPN &&
+		p.Port == 0 && p.DoHPath == "" && len(p.Other) == 0
+}
+
+// has reports whether the parameter with key is present.
+func (p SvcParams) has(key SvcParamKey) bool {
+	switch key {
+	case SvcParamMandatory:
+		return len(p.Mandatory) > 0
+	case SvcParamALPN:
+		return len(p.ALPN) > 0
+	case SvcParamNoDefaultALPN:
+		return p.NoDefaultALPN
+	case SvcParamPort:
+		return p.Port != 0
+	case SvcParamDoHPath:
+		return p.DoHPath != ""
+	}
+	for _, param := range p.Other {
+		if param.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// params returns the parameters in wire format, ordered by key.
+func (p SvcParams) params() []SvcParam {
+	var params []SvcParam
+	if len(p.Mandatory) > 0 {
+		var value []byte
+		for _, key := range p.Mandatory {
+			value = append(value, Uint16ToBytes(uint16(key))...)
+		}
+		params = append(params, SvcParam{Key: SvcParamMandatory, Value: value})
+	}
+	if len(p.ALPN) > 0 {
+		var value []byte
+		for _, id := range p.ALPN {
+			value = append(value, byte(len(id)))
+			value = append(value, id...)
+		}
+		params = append(params, SvcParam{Key: SvcParamALPN, Value: value})
+	}
+	if p.NoDefaultALPN {
+		params = append(params, SvcParam{Key: SvcParamNoDefaultALPN})
+	}
+	if p.Port != 0 {
+		params = append(params, SvcParam{Key: SvcParamPort, Value: Uint16ToBytes(p.Port)})
+	}
+	if p.DoHPath != "" {
+		params = append(params, SvcParam{Key: SvcParamDoHPath, Value: []byte(p.DoHPath)})
+	}
+	params = append(params, p.Other...)
+	sort.SliceStable(params, func(i, j int) bool { return params[i].Key < params[j].Key })
+	return params
+}
+
+func (p SvcParams) encode() []byte {
+	var buf bytes.Buffer
+	for _, param := range p.params() {
+		buf.Write(Uint16ToBytes(uint16(param.Key)))
+		buf.Write(Uint16ToBytes(uint16(len(param.Value))))
+		buf.Write(param.Value)
+	}
+	return buf.Bytes()
+}
+
+// decodeSvcParams reads service parameters, which must be in strictly
+// increasing key order (RFC 9460 section 2.2).
+func decodeSvcParams(b []byte) (SvcParams, error) {
+	var p SvcParams
+	last := -1
+	for len(b) > 0 {
+		if len(b) < 4 {
+			return p, errors.New("truncated service parameter")
+		}
+		key := SvcParamKey(BytesToUint16(b[0:2]))
+		size := int(BytesToUint16(b[2:4]))
+		if len(b) < 4+size {
+			return p, errors.New("truncated service parameter value")
+		}
+		if int(key) <= last {
+			return p, fmt.Errorf("service parameter %d out of order", key)
+		}
+		last = int(key)
+		value := b[4 : 4+size]
+		b = b[4+size:]
+		switch key {
+		case SvcParamMandatory:
+			if size == 0 || size%2 != 0 {
+				return p, errors.New("invalid mandatory keys")
+			}
+			for i := 0; i < size; i += 2 {
+				p.Mandatory = append(p.Mandatory, SvcParamKey(BytesToUint16(value[i:i+2])))
+			}
+		case SvcParamALPN:
+			if size == 0 {
+				return p, errors.New("empty alpn")
+			}
+			for data := value; len(data) > 0; {
+				n := int(data[0])
+				if n == 0 || len(data) < 1+n {
+					return p, errors.New("invalid alpn id")
+				}
+				p.ALPN = append(p.ALPN, string(data[1:1+n]))
+				data = data[1+n:]
+			}
+		case SvcParamNoDefaultALPN:
+			if size != 0 {
+				return p, errors.New("no-default-alpn has a value")
+			}
+			p.NoDefaultALPN = true
+		case SvcParamPort:
+			if size != 2 || BytesToUint16(value) == 0 {
+				return p, errors.New("invalid port")
+			}
+			p.Port = BytesToUint16(value)
+		case SvcParamDoHPath:
+			if size == 0 {
+				return p, errors.New("empty dohpath")
+			}
+			p.DoHPath = string(value)
+		default:
+			p.Other = append(p.Other, SvcParam{Key: key, Value: append([]byte(nil), value...)})
+		}
+	}
+	return p, nil
+}
+
+func (p SvcParams) validate() error {
+	if len(p.ALPN) == 0 {
+		return errors.New("alpn missing")
+	}
+	doh := false
+	for _, id := range p.ALPN {
+		if len(id) == 0 || len(id) > 255 {
+			return fmt.Errorf("invalid alpn id %q", id)
+		}
+		doh = doh || id == "h2" || id == "h3"
+	}
+	if doh && p.DoHPath == "" {
+		return errors.New("dohpath missing for DoH resolver")
+	}
+	if p.DoHPath != "" && !validDoHPath(p.DoHPath) {
+		return fmt.Errorf("invalid dohpath %q", p.DoHPath)
+	}
+	for i, key := range p.Mandatory {
+		if key == SvcParamMandatory || !p.has(key) {
+			return fmt.Errorf("invalid mandatory key %d", key)
+		}
+		if i > 0 && key <= p.Mandatory[i-1] {
+			return fmt.Errorf("mandatory key %d out of order", key)
+		}
+	}
+	seen := make(map[SvcParamKey]bool)
+	for _, param := range p.Other {
+		if param.Key <= SvcParamPort || param.Key == SvcParamDoHPath {
+			return fmt.Errorf("service parameter %d belongs in its own field", param.Key)
+		}
+		if seen[param.Key] {
+			return fmt.Errorf("duplicate service parameter %d", param.Key)
+		}
+		seen[param.Key] = true
+		if len(param.Value) > 0xffff {
+			return fmt.Errorf("service parameter %d too long", param.Key)
+		}
+	}
+	return nil
+}
+
+// validDoHPath reports whether path is a relative URI template with a
+// "dns" variable (RFC 9461 section 5).
+func validDoHPath(path string) bool {
+	if !strings.HasPrefix(path, "/") {
+		return false
+	}
+	for rest := path; ; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			return false
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return false
+		}
+		expression := strings.TrimLeft(rest[start+1:start+end], "+#./;?&")
+		for _, variable := range strings.Split(expression, ",") {
+			if strings.TrimRight(variable, "*") == "dns" {
+				return true
+			}
+		}
+		rest = rest[start+end+1:]
+	}
+}
+
+// DNRInstance is one encrypted DNS resolver. A resolver with no
+// addresses and no service parameters is in ADN-only mode: clients
+// resolve its authentication domain name to reach it.
+type DNRInstance struct {
+	Priority  uint16    `json:"priority"`
+	ADN       string    `json:"adn"`
+	Addresses []net.IP  `json:"addresses,omitempty"`
+	Params    SvcParams `json:"params"`
+}
+
+// NewDNRInstance starts building a resolver with the given service
+// priority and authentication domain name.
+//
+//	options.NewDNRInstance(1, "doh.example.net").
+//		WithAddresses("192.0.2.53").
+//		WithALPN("h2", "h3").
+//		WithDoHPath("/dns-query{?dns}")
+func NewDNRInstance(priority uint16, adn string) DNRInstance {
+	return DNRInstance{Priority: priority, ADN: adn}
+}
+
+func (r DNRInstance) WithAddresses(addresses ...string) DNRInstance {
+	r.Addresses = append([]net.IP(nil), r.Addresses...)
+	for _, address := range addresses {
+		r.Addresses = append(r.Addresses, net.ParseIP(address))
+	}
+	return r
+}
+
+func (r DNRInstance) WithALPN(ids ...string) DNRInstance {
+	r.Params.ALPN = append(append([]string(nil), r.Params.ALPN...), ids...)
+	return r
+}
+
+func (r DNRInstance) WithPort(port uint16) DNRInstance {
+	r.Params.Port = port
+	return r
+}
+
+func (r DNRInstance) WithDoHPath(path string) DNRInstance {
+	r.Params.DoHPath = path
+	return r
+}
+
+// ADNOnly reports whether the resolver is given by name only.
+func (r DNRInstance) ADNOnly() bool {
+	return len(r.Addresses) == 0 && r.Params.empty()
+}
+
+// Validate checks the resolver against RFC 9463 section 6.1.
+func (r DNRInstance) Validate() error {
+	adn, err := encodeADN(r.ADN)
+	if err != nil {
+		return err
+	}
+	if len(adn) > 255 {
+		return errors.New("authentication domain name too long")
+	}
+	if r.ADNOnly() {
+		return nil
+	}
+	if len(r.Addresses) == 0 {
+		return errors.New("service parameters without addresses")
+	}
+	if len(r.Addresses)*4 > 255 {
+		return errors.New("too many addresses")
+	}
+	for _, address := range r.Addresses {
+		ip := address.To4()
+		if ip == nil || ip.IsUnspecified() || ip.IsLoopback() || ip.IsMulticast() || ip.Equal(net.IPv4bcast) {
+			return fmt.Errorf("invalid resolver address %s", address)
+		}
+	}
+	if err := r.Params.validate(); err != nil {
+		return err
+	}
+	if len(r.encode()) > 0xffff {
+		return errors.New("resolver instance too long")
+	}
+	return nil
+}
+
+// encode returns the DNR instance data without its length.
+func (r DNRInstance) encode() []byte {
+	var buf bytes.Buffer
+	buf.Write(Uint16ToBytes(r.Priority))
+	adn, _ := encodeADN(r.ADN)
+	buf.WriteByte(byte(len(adn)))
+	buf.Write(adn)
+	if r.ADNOnly() {
+		return buf.Bytes()
+	}
+	buf.WriteByte(byte(len(r.Addresses) * 4))
+	for _, address := range r.Addresses {
+		buf.Write(address.To4())
+	}
+	buf.Write(r.Params.encode())
+	return buf.Bytes()
+}
+
+func decodeDNRInstance(b []byte) (DNRInstance, error) {
+	var r DNRInstance
+	if len(b) < 3 {
+		return r, errors.New("truncated resolver instance")
+	}
+	r.Priority = BytesToUint16(b[0:2])
+	size := int(b[2])
+	if len(b) < 3+size {
+		return r, errors.New("truncated authentication domain name")
+	}
+	adn, err := decodeADN(b[3 : 3+size])
+	if err != nil {
+		return r, err
+	}
+	r.ADN = adn
+	b = b[3+size:]
+	if len(b) == 0 {
+		return r, nil
+	}
+	size = int(b[0])
+	if size == 0 || size%4 != 0 || len(b) < 1+size {
+		return r, errors.New("invalid address length")
+	}
+	r.Addresses = decodeIPs(b[1 : 1+size])
+	r.Params, err = decodeSvcParams(b[1+size:])
+	if err != nil {
+		return r, err
+	}
+	return r, r.Validate()
+}
+
+// encodeADN returns name in uncompressed DNS wire format.
+func encodeADN(name string) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		return nil, errors.New("authentication domain name missing")
+	}
+	var buf bytes.Buffer
+	for _, label := range strings.Split(name, ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid label in authentication domain name %q", name)
+		}
+		buf.WriteByte(byte(len(label)))
+		buf.WriteString(label)
+	}
+	buf.WriteByte(0)
+	return buf.Bytes(), nil
+}
+
+// decodeADN reads an authentication domain name, which must fill b and
+// must not use compression (RFC 9463 section 6.1).
+func decodeADN(b []byte) (string, error) {
+	var labels []string
+	for len(b) > 0 {
+		size := int(b[0])
+		if size == 0 {
+			if len(b) != 1 {
+				return "", errors.New("data after terminating label")
+			}
+			if len(labels) == 0 {
+				break
+			}
+			return strings.Join(labels, "."), nil
+		}
+		if size > 63 || len(b) < 1+size {
+			return "", errors.New("invalid label in authentication domain name")
+		}
+		labels = append(labels, string(b[1:1+size]))
+		b = b[1+size:]
+	}
+	return "", errors.New("invalid authentication domain name")
+}
+
+// Option162 DHCP Encrypted DNS (DNR)
+// https://www.rfc-editor.org/rfc/rfc9463#section-6.1
+// This option lists encrypted DNS resolvers (DoT, DoH, DoQ) the client
+// may use, in one or more DNR instances.  The option is usually longer
+// than 255 octets and is then split over several options as described
+// in RFC 3396.
+//
+//	 Code   Len
+//	+-----+-----+
+//	| 162 |  n  |  DNR Instance 1 | DNR Instance 2 | ...
+//	+-----+-----+
+//
+// Each DNR instance is
+//
+//	+-------------------+------------------+-------+----------------+
+//	| Instance Data Len | Service Priority | ADN   | ADN            |
+//	|    (2 octets)     |    (2 octets)    | Len   | (DNS wire fmt) |
+//	+-------------------+------------------+-------+----------------+
+//	| Addr Len | IPv4 address(es) | SvcParams                       |
+//	+----------+------------------+---------------------------------+
+//
+// The last three fields are absent in ADN-only mode.
+type EncryptedDNSOption struct {
+	Resolvers []DNRInstance `json:"resolvers"`
+}
+
+// NewEncryptedDNSOption lists resolvers built with NewDNRInstance.
+// Resolvers that fail Validate are left out when the option is encoded;
+// call Validate on the result to find them.
+func NewEncryptedDNSOption(resolvers ...DNRInstance) Option {
+	return EncryptedDNSOption{Resolvers: resolvers}
+}
+
+func (o EncryptedDNSOption) Code() OptionCode {
+	return OptionCodeEncryptedDNS
+}
+
+// Validate checks every resolver of the option.
+func (o EncryptedDNSOption) Validate() error {
+	if len(o.Resolvers) == 0 {
+		return errors.New("no resolvers")
+	}
+	for i, r := range o.Resolvers {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("resolver %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (o EncryptedDNSOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, r := range o.Resolvers {
+		if r.Validate() != nil {
+			continue
+		}
+		data := r.encode()
+		buf.Write(Uint16ToBytes(uint16(len(data))))
+		buf.Write(data)
+	}
+	return buf.Bytes()
+}
+
+func (o EncryptedDNSOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o EncryptedDNSOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 2); err != nil {
+		return o, err
+	}
+	o.Resolvers = nil
+	for len(b) > 0 {
+		if len(b) < 2 {
+			return o, errors.New("truncated resolver instance length")
+		}
+		size := int(BytesToUint16(b[0:2]))
+		if len(b) < 2+size {
+			return o, errors.New("truncated resolver instance")
+		}
+		r, err := decodeDNRInstance(b[2 : 2+size])
+		if err != nil {
+			return o, fmt.Errorf("resolver %d: %w", len(o.Resolvers), err)
+		}
+		o.Resolvers = append(o.Resolvers, r)
+		b = b[2+size:]
+	}
+	return o, nil
+}
+
+func (o EncryptedDNSOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" Encrypted DNS:")
+	for _, r := range o.Resolvers {
+		buf.WriteString(fmt.Sprintf(" [%d %s", r.Priority, r.ADN))
+		if !r.ADNOnly() {
+			buf.WriteString(fmt.Sprintf(" %v alpn=%s", r.Addresses, strings.Join(r.Params.ALPN, ",")))
+			if r.Params.Port != 0 {
+				buf.WriteString(fmt.Sprintf(" port=%d", r.Params.Port))
+			}
+			if r.Params.DoHPath != "" {
+				buf.WriteString(fmt.Sprintf(" dohpath=%s", r.Params.DoHPath))
+			}
+		}
+		buf.WriteString("]")
+	}
+	return buf.String()
+}
@@ -0,0 +1,62 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option36 Ethernet Encapsulation Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-6.3
+// This option specifies whether or not the client should use Ethernet
+// Version 2 (RFC 894) or IEEE 802.3 (RFC 1042) encapsulation if the
+// interface is an Ethernet. A value of 0 indicates that the client
+// should use RFC 894 encapsulation. A value of 1 means that the client
+// should use RFC 1042 encapsulation. The code for this option is 36, and
+// its length is 1.
+//
+//	 Code   Len   Value
+//	+-----+-----+-----+
+//	| 36  |  1  | 0/1 |
+//	+-----+-----+-----+
+type EthernetEncapsulationOption struct {
+	IEEE8023 bool `json:"ieee8023"`
+}
+
+func NewEthernetEncapsulationOption(enabled bool) Option {
+	return EthernetEncapsulationOption{
+		IEEE8023: enabled,
+	}
+}
+
+func (o EthernetEncapsulationOption) Code() OptionCode {
+	return OptionCodeEthernetEncapsulation
+}
+
+func (o EthernetEncapsulationOption) Encode() []byte {
+	return boolToBytes(o.IEEE8023)
+}
+
+func (o EthernetEncapsulationOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o EthernetEncapsulationOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	value, err := bytesToBool(b)
+	if err != nil {
+		return o, err
+	}
+	o.IEEE8023 = value
+	return o, nil
+}
+
+func (o EthernetEncapsulationOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" IEEE 802.3 Encapsulation: %t", o.IEEE8023))
+	return buf.String()
+}
@@ -0,0 +1,56 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option18 Extensions Path
+// https://www.rfc-editor.org/rfc/rfc2132#section-3.20
+// A string to specify a file, retrievable via TFTP, which contains
+// information which can be interpreted in the same way as the 64-octet
+// vendor-extension field within the BOOTP response. The code for this
+// option is 18. Its minimum length is 1.
+//
+//	 Code   Len   Extensions Pathname
+//	+-----+-----+-----+-----+-----+-----+--
+//	| 18  |  n  | n1  | n2  | n3  | n4  |  ...
+//	+-----+-----+-----+-----+-----+-----+--
+type ExtensionsPathOption struct {
+	ExtensionsPath string `json:"path"`
+}
+
+func NewExtensionsPathOption(path string) Option {
+	return ExtensionsPathOption{
+		ExtensionsPath: path,
+	}
+}
+
+func (o ExtensionsPathOption) Code() OptionCode {
+	return OptionCodeExtensionsPath
+}
+
+func (o ExtensionsPathOption) Encode() []byte {
+	return []byte(o.ExtensionsPath)
+}
+
+func (o ExtensionsPathOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ExtensionsPathOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.ExtensionsPath = string(b)
+	return o, nil
+}
+
+func (o ExtensionsPathOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Extensions Pathname: %s", o.ExtensionsPath))
+	return buf.String()
+}
@@ -0,0 +1,65 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option145 Forcerenew Nonce Capable
+// https://www.rfc-editor.org/rfc/rfc6704#section-3.1.2
+// A client includes this option in DHCPDISCOVER and DHCPREQUEST to list
+// the algorithms it supports for authenticating DHCPFORCERENEW messages
+// with a nonce.  The only algorithm defined is HMAC-MD5 (1).
+//
+//	 Code  Len  Algorithm 1  Algorithm 2
+//	+-----+-----+-----------+-----------+---
+//	| 145 |  n  |     A1    |     A2    | ...
+//	+-----+-----+-----------+-----------+---
+type ForcerenewNonceCapableOption struct {
+	Algorithms []uint8 `json:"algorithms"`
+}
+
+func NewForcerenewNonceCapableOption(algorithms ...uint8) Option {
+	return ForcerenewNonceCapableOption{
+		Algorithms: algorithms,
+	}
+}
+
+func (o ForcerenewNonceCapableOption) Code() OptionCode {
+	return OptionCodeForcerenewNonceCapable
+}
+
+func (o ForcerenewNonceCapableOption) Encode() []byte {
+	return o.Algorithms
+}
+
+func (o ForcerenewNonceCapableOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ForcerenewNonceCapableOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.Algorithms = b
+	return o, nil
+}
+
+// Supports reports whether algorithm is listed.
+func (o ForcerenewNonceCapableOption) Supports(algorithm uint8) bool {
+	for _, a := range o.Algorithms {
+		if a == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+func (o ForcerenewNonceCapableOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Algorithms: %v", o.Algorithms))
+	return buf.String()
+}
@@ -32,8 +32,16 @@ func (o HostNameOption) Encode() []byte {
 }
 
 func (o HostNameOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o HostNameOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
 	o.HostName = string(b)
-	return o
+	return o, nil
 }
 
 func (o HostNameOption) String() string {
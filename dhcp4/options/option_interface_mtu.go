@@ -0,0 +1,58 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option26 Interface MTU Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-5.1
+// This option specifies the MTU to use on this interface. The MTU is
+// specified as a 16-bit unsigned integer. The minimum legal value for
+// the MTU is 68. The code for this option is 26, and its length is 2.
+//
+//	 Code   Len   Interface MTU
+//	+-----+-----+-----+-----+
+//	| 26  |  2  | l1  | l2  |
+//	+-----+-----+-----+-----+
+type InterfaceMTUOption struct {
+	MTU uint16 `json:"mtu"`
+}
+
+func NewInterfaceMTUOption(mtu uint16) Option {
+	return InterfaceMTUOption{
+		MTU: mtu,
+	}
+}
+
+func (o InterfaceMTUOption) Code() OptionCode {
+	return OptionCodeInterfaceMTU
+}
+
+func (o InterfaceMTUOption) Encode() []byte {
+	return Uint16ToBytes(o.MTU)
+}
+
+func (o InterfaceMTUOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o InterfaceMTUOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 2); err != nil {
+		return o, err
+	}
+	if value := BytesToUint16(b); value < 68 {
+		return o, fmt.Errorf("interface MTU %d below 68", value)
+	}
+	o.MTU = BytesToUint16(b)
+	return o, nil
+}
+
+func (o InterfaceMTUOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Interface MTU: %d", o.MTU))
+	return buf.String()
+}
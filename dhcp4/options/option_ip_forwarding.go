@@ -0,0 +1,60 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option19 IP Forwarding Enable/Disable Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-4.1
+// This option specifies whether the client should configure its IP layer
+// for packet forwarding. A value of 0 means disable IP forwarding, and a
+// value of 1 means enable IP forwarding. The code for this option is 19,
+// and its length is 1.
+//
+//	 Code   Len   Value
+//	+-----+-----+-----+
+//	| 19  |  1  | 0/1 |
+//	+-----+-----+-----+
+type IPForwardingOption struct {
+	Enabled bool `json:"enabled"`
+}
+
+func NewIPForwardingOption(enabled bool) Option {
+	return IPForwardingOption{
+		Enabled: enabled,
+	}
+}
+
+func (o IPForwardingOption) Code() OptionCode {
+	return OptionCodeIPForwarding
+}
+
+func (o IPForwardingOption) Encode() []byte {
+	return boolToBytes(o.Enabled)
+}
+
+func (o IPForwardingOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o IPForwardingOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	value, err := bytesToBool(b)
+	if err != nil {
+		return o, err
+	}
+	o.Enabled = value
+	return o, nil
+}
+
+func (o IPForwardingOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" IP Forwarding: %t", o.Enabled))
+	return buf.String()
+}
@@ -0,0 +1,81 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// MinV6OnlyWait is the shortest V6ONLY_WAIT a client honours, in seconds
+// (MIN_V6ONLY_WAIT, RFC 8925 section 3.4).
+const MinV6OnlyWait = 300
+
+// Option108 IPv6-Only Preferred Option
+// https://www.rfc-editor.org/rfc/rfc8925#section-3.1
+// Code:
+// 8-bit identifier of the IPv6-Only Preferred option code as assigned by IANA: 108.
+// The client includes the Code in the Parameter Request List in DHCPDISCOVER and DHCPREQUEST messages as described in Section 3.2.
+// Length:
+// 8-bit unsigned integer. The length of the option, excluding the Code and Length Fields.
+// The server MUST set the length field to 4. The client MUST ignore the IPv6-Only Preferred option if the length field value is not 4.
+// Value:
+// 32-bit unsigned integer. The number of seconds for which the client should disable DHCPv4 (V6ONLY_WAIT configuration variable).
+// If the server pool is explicitly configured with a V6ONLY_WAIT timer,
+// the server MUST set the field to that configured value. Otherwise,
+// the server MUST set it to zero. The client MUST process that field as described in Section 3.2.
+//
+// The client never sets this field, as it never sends the full option
+// but includes the option code in the Parameter Request List as described in Section 3.2.
+// 0                   1                   2                   3
+//
+//	0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+//
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |     Code      |   Length      |           Value               |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+// |         Value (cont.)         |
+// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+type IPv6OnlyPreferredOption struct {
+	V6OnlyWait uint32 `json:"v6only_wait"`
+}
+
+func NewIPv6OnlyPreferredOption(v6OnlyWait uint32) Option {
+	return IPv6OnlyPreferredOption{V6OnlyWait: v6OnlyWait}
+}
+
+func (o IPv6OnlyPreferredOption) Code() OptionCode {
+	return OptionCodeIPv6OnlyPreferred
+}
+
+func (o IPv6OnlyPreferredOption) Encode() []byte {
+	return Uint32ToBytes(o.V6OnlyWait)
+}
+
+func (o IPv6OnlyPreferredOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o IPv6OnlyPreferredOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.V6OnlyWait = BytesToUint32(b)
+	return o, nil
+}
+
+// Wait returns the number of seconds a client disables DHCPv4 for, which
+// is never less than MinV6OnlyWait.
+func (o IPv6OnlyPreferredOption) Wait() uint32 {
+	if o.V6OnlyWait < MinV6OnlyWait {
+		return MinV6OnlyWait
+	}
+	return o.V6OnlyWait
+}
+
+func (o IPv6OnlyPreferredOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" V6OnlyWait: %d", o.V6OnlyWait))
+	return buf.String()
+}
@@ -40,8 +40,16 @@ func (o LeaseTimeOption) Encode() []byte {
 }
 
 func (o LeaseTimeOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o LeaseTimeOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
 	o.LeaseTime = BytesToUint32(b)
-	return o
+	return o, nil
 }
 
 func (o LeaseTimeOption) String() string {
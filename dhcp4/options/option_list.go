@@ -0,0 +1,70 @@
+package options
+
+// Options is an ordered collection of DHCP options. It keeps options in
+// the order they were decoded or added and may hold several options with
+// the same code.
+type Options []Option
+
+// Get returns the first option with the given code, or nil.
+func (o Options) Get(code OptionCode) Option {
+	for _, option := range o {
+		if option.Code() == code {
+			return option
+		}
+	}
+	return nil
+}
+
+// GetAll returns every option with the given code in order.
+func (o Options) GetAll(code OptionCode) []Option {
+	var all []Option
+	for _, option := range o {
+		if option.Code() == code {
+			all = append(all, option)
+		}
+	}
+	return all
+}
+
+// Has reports whether an option with the given code is present.
+func (o Options) Has(code OptionCode) bool {
+	return o.Get(code) != nil
+}
+
+// Set replaces the options with the same code as option, keeping the
+// position of the first one, or appends option if there is none.
+func (o *Options) Set(option Option) {
+	code := option.Code()
+	list := (*o)[:0]
+	replaced := false
+	for _, current := range *o {
+		if current.Code() != code {
+			list = append(list, current)
+			continue
+		}
+		if !replaced {
+			list = append(list, option)
+			replaced = true
+		}
+	}
+	if !replaced {
+		list = append(list, option)
+	}
+	*o = list
+}
+
+// Add appends option, keeping any options with the same code.
+func (o *Options) Add(option Option) {
+	*o = append(*o, option)
+}
+
+// Del removes every option with the given code.
+func (o *Options) Del(code OptionCode) {
+	list := (*o)[:0]
+	for _, option := range *o {
+		if option.Code() != code {
+			list = append(list, option)
+		}
+	}
+	*o = list
+}
@@ -0,0 +1,68 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// Option9 LPR Server Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-3.11
+// The LPR server option specifies a list of RFC 1179 line printer
+// servers available to the client. Servers SHOULD be listed in order of
+// preference. The code for the LPR server option is 9. The minimum
+// length for this option is 4 octets, and the length MUST always be a
+// multiple of 4.
+//
+//	 Code   Len   Address 1               Address 2
+//	+-----+-----+-----+-----+-----+-----+-----+-----+--
+//	|  9  |  n  | a1  | a2  | a3  | a4  | a1  | a2  |  ...
+//	+-----+-----+-----+-----+-----+-----+-----+-----+--
+type LPRServerOption struct {
+	Servers []net.IP
+}
+
+func NewLPRServerOption(servers []string) Option {
+	o := LPRServerOption{}
+	for _, server := range servers {
+		o.Servers = append(o.Servers, net.ParseIP(server))
+	}
+	return o
+}
+
+func (o LPRServerOption) Code() OptionCode {
+	return OptionCodeLPRServer
+}
+
+func (o LPRServerOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, server := range o.Servers {
+		buf.Write(server.To4())
+	}
+	return buf.Bytes()
+}
+
+func (o LPRServerOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o LPRServerOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 4); err != nil {
+		return o, err
+	}
+	o.Servers = decodeIPs(b)
+	return o, nil
+}
+
+func (o LPRServerOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" LPR Servers:")
+	for _, server := range o.Servers {
+		buf.WriteString(" ")
+		buf.WriteString(server.String())
+	}
+	return buf.String()
+}
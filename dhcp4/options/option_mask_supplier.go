@@ -0,0 +1,60 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option30 Mask Supplier Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-5.5
+// This option specifies whether or not the client should respond to
+// subnet mask requests using ICMP. A value of 0 indicates that the
+// client should not respond. A value of 1 means that the client should
+// respond. The code for this option is 30, and its length is 1.
+//
+//	 Code   Len   Value
+//	+-----+-----+-----+
+//	| 30  |  1  | 0/1 |
+//	+-----+-----+-----+
+type MaskSupplierOption struct {
+	Enabled bool `json:"enabled"`
+}
+
+func NewMaskSupplierOption(enabled bool) Option {
+	return MaskSupplierOption{
+		Enabled: enabled,
+	}
+}
+
+func (o MaskSupplierOption) Code() OptionCode {
+	return OptionCodeMaskSupplier
+}
+
+func (o MaskSupplierOption) Encode() []byte {
+	return boolToBytes(o.Enabled)
+}
+
+func (o MaskSupplierOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o MaskSupplierOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	value, err := bytesToBool(b)
+	if err != nil {
+		return o, err
+	}
+	o.Enabled = value
+	return o, nil
+}
+
+func (o MaskSupplierOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Mask Supplier: %t", o.Enabled))
+	return buf.String()
+}
@@ -0,0 +1,59 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option22 Maximum Datagram Reassembly Size
+// https://www.rfc-editor.org/rfc/rfc2132#section-4.1
+// This option specifies the maximum size datagram that the client should
+// be prepared to reassemble. The size is specified as a 16-bit unsigned
+// integer. The minimum value legal value is 576. The code for this
+// option is 22, and its length is 2.
+//
+//	 Code   Len   Maximum Datagram Reassembly Size
+//	+-----+-----+-----+-----+
+//	| 22  |  2  | l1  | l2  |
+//	+-----+-----+-----+-----+
+type MaxDatagramReassemblyOption struct {
+	MaxDatagramReassembly uint16 `json:"max_datagram_reassembly"`
+}
+
+func NewMaxDatagramReassemblyOption(size uint16) Option {
+	return MaxDatagramReassemblyOption{
+		MaxDatagramReassembly: size,
+	}
+}
+
+func (o MaxDatagramReassemblyOption) Code() OptionCode {
+	return OptionCodeMaxDatagramReassembly
+}
+
+func (o MaxDatagramReassemblyOption) Encode() []byte {
+	return Uint16ToBytes(o.MaxDatagramReassembly)
+}
+
+func (o MaxDatagramReassemblyOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o MaxDatagramReassemblyOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 2); err != nil {
+		return o, err
+	}
+	if value := BytesToUint16(b); value < 576 {
+		return o, fmt.Errorf("maximum datagram reassembly size %d below 576", value)
+	}
+	o.MaxDatagramReassembly = BytesToUint16(b)
+	return o, nil
+}
+
+func (o MaxDatagramReassemblyOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Maximum Datagram Reassembly Size: %d", o.MaxDatagramReassembly))
+	return buf.String()
+}
@@ -40,8 +40,16 @@ func (o MaximumMessageSizeOption) Encode() []byte {
 }
 
 func (o MaximumMessageSizeOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o MaximumMessageSizeOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 2); err != nil {
+		return o, err
+	}
 	o.MaximumMessageSize = BytesToUint16(b)
-	return o
+	return o, nil
 }
 
 func (o MaximumMessageSizeOption) String() string {
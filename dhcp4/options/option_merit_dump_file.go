@@ -0,0 +1,57 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option14 Merit Dump File
+// https://www.rfc-editor.org/rfc/rfc2132#section-3.16
+// This option specifies the path-name of a file to which the client's
+// core image should be dumped in the event the client crashes. The path
+// is formatted as a character string consisting of characters from the
+// NVT ASCII character set. The code for this option is 14. Its minimum
+// length is 1.
+//
+//	 Code   Len   Dump File Pathname
+//	+-----+-----+-----+-----+-----+-----+--
+//	| 14  |  n  | n1  | n2  | n3  | n4  |  ...
+//	+-----+-----+-----+-----+-----+-----+--
+type DumpFileOption struct {
+	DumpFile string `json:"path"`
+}
+
+func NewDumpFileOption(path string) Option {
+	return DumpFileOption{
+		DumpFile: path,
+	}
+}
+
+func (o DumpFileOption) Code() OptionCode {
+	return OptionCodeDumpFile
+}
+
+func (o DumpFileOption) Encode() []byte {
+	return []byte(o.DumpFile)
+}
+
+func (o DumpFileOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o DumpFileOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.DumpFile = string(b)
+	return o, nil
+}
+
+func (o DumpFileOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Dump File Pathname: %s", o.DumpFile))
+	return buf.String()
+}
@@ -46,6 +46,14 @@ func (o MessageOption) String() string {
 }
 
 func (o MessageOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o MessageOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
 	o.Text = string(b)
-	return o
+	return o, nil
 }
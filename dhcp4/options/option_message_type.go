@@ -46,6 +46,22 @@ func (o MessageType) String() string {
 		return "Release"
 	case DHCPINFORM:
 		return "Inform"
+	case DHCPFORCERENEW:
+		return "ForceRenew"
+	case DHCPLEASEQUERY:
+		return "LeaseQuery"
+	case DHCPLEASEUNASSIGNED:
+		return "LeaseUnassigned"
+	case DHCPLEASEUNKNOWN:
+		return "LeaseUnknown"
+	case DHCPLEASEACTIVE:
+		return "LeaseActive"
+	case DHCPBULKLEASEQUERY:
+		return "BulkLeaseQuery"
+	case DHCPLEASEQUERYDONE:
+		return "LeaseQueryDone"
+	case DHCPLEASEQUERYSTATUS:
+		return "LeaseQueryStatus"
 	default:
 		return "Invalid"
 	}
@@ -84,8 +100,16 @@ func (o MessageTypeOption) Encode() []byte {
 }
 
 func (o MessageTypeOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o MessageTypeOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
 	o.Type = MessageType(b[0])
-	return o
+	return o, nil
 }
 
 func (o MessageTypeOption) String() string {
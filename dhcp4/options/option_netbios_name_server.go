@@ -0,0 +1,67 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// Option44 NetBIOS over TCP/IP Name Server Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-8.5
+// The NetBIOS name server (NBNS) option specifies a list of RFC
+// 1001/1002 NBNS name servers listed in order of preference. The code
+// for this option is 44. The minimum length of the option is 4 octets,
+// and the length must always be a multiple of 4.
+//
+//	 Code   Len   Address 1               Address 2
+//	+-----+-----+-----+-----+-----+-----+-----+-----+--
+//	| 44  |  n  | a1  | a2  | a3  | a4  | a1  | a2  |  ...
+//	+-----+-----+-----+-----+-----+-----+-----+-----+--
+type NetBIOSNameServerOption struct {
+	Servers []net.IP
+}
+
+func NewNetBIOSNameServerOption(servers []string) Option {
+	o := NetBIOSNameServerOption{}
+	for _, server := range servers {
+		o.Servers = append(o.Servers, net.ParseIP(server))
+	}
+	return o
+}
+
+func (o NetBIOSNameServerOption) Code() OptionCode {
+	return OptionCodeNetBIOSNameServer
+}
+
+func (o NetBIOSNameServerOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, server := range o.Servers {
+		buf.Write(server.To4())
+	}
+	return buf.Bytes()
+}
+
+func (o NetBIOSNameServerOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o NetBIOSNameServerOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 4); err != nil {
+		return o, err
+	}
+	o.Servers = decodeIPs(b)
+	return o, nil
+}
+
+func (o NetBIOSNameServerOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" NetBIOS Name Servers:")
+	for _, server := range o.Servers {
+		buf.WriteString(" ")
+		buf.WriteString(server.String())
+	}
+	return buf.String()
+}
@@ -0,0 +1,56 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option40 Network Information Service Domain Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-8.1
+// This option specifies the name of the client's NIS domain. The domain
+// is formatted as a character string consisting of characters from the
+// NVT ASCII character set. The code for this option is 40. Its minimum
+// length is 1.
+//
+//	 Code   Len   NIS Domain Name
+//	+-----+-----+-----+-----+-----+-----+--
+//	| 40  |  n  | n1  | n2  | n3  | n4  |  ...
+//	+-----+-----+-----+-----+-----+-----+--
+type NetworkInformationServiceDomainOption struct {
+	Domain string `json:"domain"`
+}
+
+func NewNetworkInformationServiceDomainOption(domain string) Option {
+	return NetworkInformationServiceDomainOption{
+		Domain: domain,
+	}
+}
+
+func (o NetworkInformationServiceDomainOption) Code() OptionCode {
+	return OptionCodeNetworkInformationServiceDomain
+}
+
+func (o NetworkInformationServiceDomainOption) Encode() []byte {
+	return []byte(o.Domain)
+}
+
+func (o NetworkInformationServiceDomainOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o NetworkInformationServiceDomainOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.Domain = string(b)
+	return o, nil
+}
+
+func (o NetworkInformationServiceDomainOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" NIS Domain Name: %s", o.Domain))
+	return buf.String()
+}
@@ -0,0 +1,67 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// Option41 Network Information Servers Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-8.2
+// This option specifies a list of IP addresses indicating NIS servers
+// available to the client. Servers SHOULD be listed in order of
+// preference. The code for this option is 41. Its minimum length is 4,
+// and the length MUST be a multiple of 4.
+//
+//	 Code   Len   Address 1               Address 2
+//	+-----+-----+-----+-----+-----+-----+-----+-----+--
+//	| 41  |  n  | a1  | a2  | a3  | a4  | a1  | a2  |  ...
+//	+-----+-----+-----+-----+-----+-----+-----+-----+--
+type NetworkInformationServersOption struct {
+	Servers []net.IP
+}
+
+func NewNetworkInformationServersOption(servers []string) Option {
+	o := NetworkInformationServersOption{}
+	for _, server := range servers {
+		o.Servers = append(o.Servers, net.ParseIP(server))
+	}
+	return o
+}
+
+func (o NetworkInformationServersOption) Code() OptionCode {
+	return OptionCodeNetworkInformationServers
+}
+
+func (o NetworkInformationServersOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, server := range o.Servers {
+		buf.Write(server.To4())
+	}
+	return buf.Bytes()
+}
+
+func (o NetworkInformationServersOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o NetworkInformationServersOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 4); err != nil {
+		return o, err
+	}
+	o.Servers = decodeIPs(b)
+	return o, nil
+}
+
+func (o NetworkInformationServersOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" NIS Servers:")
+	for _, server := range o.Servers {
+		buf.WriteString(" ")
+		buf.WriteString(server.String())
+	}
+	return buf.String()
+}
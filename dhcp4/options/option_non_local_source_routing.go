@@ -0,0 +1,61 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option20 Non-Local Source Routing Enable/Disable Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-4.2
+// This option specifies whether the client should configure its IP layer
+// to allow forwarding of datagrams with non-local source routes. A value
+// of 0 means disallow forwarding of such datagrams, and a value of 1
+// means allow forwarding. The code for this option is 20, and its length
+// is 1.
+//
+//	 Code   Len   Value
+//	+-----+-----+-----+
+//	| 20  |  1  | 0/1 |
+//	+-----+-----+-----+
+type NonLocalSourceRoutingOption struct {
+	Enabled bool `json:"enabled"`
+}
+
+func NewNonLocalSourceRoutingOption(enabled bool) Option {
+	return NonLocalSourceRoutingOption{
+		Enabled: enabled,
+	}
+}
+
+func (o NonLocalSourceRoutingOption) Code() OptionCode {
+	return OptionCodeNonLocalSourceRouting
+}
+
+func (o NonLocalSourceRoutingOption) Encode() []byte {
+	return boolToBytes(o.Enabled)
+}
+
+func (o NonLocalSourceRoutingOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o NonLocalSourceRoutingOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	value, err := bytesToBool(b)
+	if err != nil {
+		return o, err
+	}
+	o.Enabled = value
+	return o, nil
+}
+
+func (o NonLocalSourceRoutingOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Non-Local Source Routing: %t", o.Enabled))
+	return buf.String()
+}
@@ -33,13 +33,17 @@ func (o NetworkTimeProtocolServersOption) Encode() []byte {
 	return buf.Bytes()
 }
 
-func (o NetworkTimeProtocolServersOption) Decode(data []byte) Option {
-	o.Servers = nil
-	for len(data) >= 4 {
-		o.Servers = append(o.Servers, net.IPv4(data[0], data[1], data[2], data[3]))
-		data = data[4:]
+func (o NetworkTimeProtocolServersOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o NetworkTimeProtocolServersOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 4); err != nil {
+		return o, err
 	}
-	return o
+	o.Servers = decodeIPs(b)
+	return o, nil
 }
 
 func (o NetworkTimeProtocolServersOption) String() string {
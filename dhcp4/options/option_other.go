@@ -6,56 +6,6 @@ import (
 	"net"
 )
 
-// Option108 IPv6-Only Preferred Option
-// Code:
-// 8-bit identifier of the IPv6-Only Preferred option code as assigned by IANA: 108.
-// The client includes the Code in the Parameter Request List in DHCPDISCOVER and DHCPREQUEST messages as described in Section 3.2.
-// Length:
-// 8-bit unsigned integer. The length of the option, excluding the Code and Length Fields.
-// The server MUST set the length field to 4. The client MUST ignore the IPv6-Only Preferred option if the length field value is not 4.
-// Value:
-// 32-bit unsigned integer. The number of seconds for which the client should disable DHCPv4 (V6ONLY_WAIT configuration variable).
-// If the server pool is explicitly configured with a V6ONLY_WAIT timer,
-// the server MUST set the field to that configured value. Otherwise,
-// the server MUST set it to zero. The client MUST process that field as described in Section 3.2.
-//
-// The client never sets this field, as it never sends the full option
-// but includes the option code in the Parameter Request List as described in Section 3.2.
-// 0                   1                   2                   3
-//
-//	0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
-//
-// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
-// |     Code      |   Length      |           Value               |
-// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
-// |         Value (cont.)         |
-// +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
-type Option108 struct {
-	Value []byte
-}
-
-func (o Option108) Code() OptionCode {
-	return 108
-}
-
-func (o Option108) Encode() []byte {
-	return o.Value
-}
-
-func (o Option108) Decode(b []byte) Option {
-	o.Value = b
-	return o
-}
-
-func (o Option108) String() string {
-	var buf bytes.Buffer
-	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
-	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
-	buf.WriteString("IPv6-Only Preferred:")
-	buf.WriteString(fmt.Sprintf(" Value: %x", o.Value))
-	return buf.String()
-}
-
 /*
 Option138
 The DHCPv4 option for CAPWAP has the format shown in the following
@@ -88,17 +38,23 @@ func (o Option138) Code() OptionCode {
 
 func (o Option138) Encode() []byte {
 	var buf bytes.Buffer
-	for i := 0; i < len(o.Addresses); i += 4 {
-		buf.Write(o.Addresses[i])
+	for _, address := range o.Addresses {
+		buf.Write(address.To4())
 	}
 	return buf.Bytes()
 }
 
 func (o Option138) Decode(b []byte) Option {
-	for i := 0; i < len(b); i += 4 {
-		o.Addresses = append(o.Addresses, b[i:i+4])
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o Option138) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 4); err != nil {
+		return o, err
 	}
-	return o
+	o.Addresses = decodeIPs(b)
+	return o, nil
 }
 
 func (o Option138) String() string {
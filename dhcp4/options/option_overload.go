@@ -0,0 +1,84 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option52 Option Overload
+// https://www.rfc-editor.org/rfc/rfc2132#section-9.3
+// This option is used to indicate that the DHCP 'sname' or 'file'
+//
+//	fields are being overloaded by using them to carry DHCP options. A
+//	DHCP server inserts this option if the returned parameters will
+//	exceed the usual space allotted for options.
+//
+//	The code for this option is 52, and its length is 1.  Legal values
+//	for this option are:
+//
+//	        Value   Meaning
+//	        -----   --------
+//	          1     the 'file' field is used to hold options
+//	          2     the 'sname' field is used to hold options
+//	          3     both fields are used to hold options
+//
+//	 Code   Len  Value
+//	+-----+-----+-----+
+//	|  52 |  1  |1/2/3|
+//	+-----+-----+-----+
+type OverloadOption struct {
+	Overload uint8 `json:"overload"`
+}
+
+// Values of the option overload option.
+const (
+	OverloadFile  uint8 = 1
+	OverloadSName uint8 = 2
+	OverloadBoth  uint8 = OverloadFile | OverloadSName
+)
+
+func NewOverloadOption(overload uint8) Option {
+	return OverloadOption{Overload: overload}
+}
+
+func (o OverloadOption) Code() OptionCode {
+	return OptionCodeOverload
+}
+
+func (o OverloadOption) Encode() []byte {
+	return []byte{o.Overload}
+}
+
+func (o OverloadOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o OverloadOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	if b[0] < OverloadFile || b[0] > OverloadBoth {
+		return o, fmt.Errorf("invalid overload value %d", b[0])
+	}
+	o.Overload = b[0]
+	return o, nil
+}
+
+// File reports whether the 'file' field holds options.
+func (o OverloadOption) File() bool {
+	return o.Overload&OverloadFile != 0
+}
+
+// SName reports whether the 'sname' field holds options.
+func (o OverloadOption) SName() bool {
+	return o.Overload&OverloadSName != 0
+}
+
+func (o OverloadOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Overload: %d", o.Overload))
+	return buf.String()
+}
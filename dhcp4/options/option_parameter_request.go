@@ -36,11 +36,19 @@ func (o ParameterRequestOption) Encode() []byte {
 }
 
 func (o ParameterRequestOption) Decode(b []byte) Option {
-	o.Parameters = make([]OptionCode, 0)
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ParameterRequestOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.Parameters = make([]OptionCode, 0, len(b))
 	for i := 0; i < len(b); i++ {
 		o.Parameters = append(o.Parameters, OptionCode(b[i]))
 	}
-	return o
+	return o, nil
 }
 
 func (o ParameterRequestOption) String() string {
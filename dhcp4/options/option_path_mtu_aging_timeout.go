@@ -0,0 +1,56 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option24 Path MTU Aging Timeout Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-4.3
+// This option specifies the timeout (in seconds) to use when aging Path
+// MTU values discovered by the mechanism defined in RFC 1191. The
+// timeout is specified as a 32-bit unsigned integer. The code for this
+// option is 24, and its length is 4.
+//
+//	 Code   Len   Path MTU Aging Timeout
+//	+-----+-----+-----+-----+-----+-----+
+//	| 24  |  4  | t1  | t2  | t3  | t4  |
+//	+-----+-----+-----+-----+-----+-----+
+type PathMTUAgingTimeoutOption struct {
+	Timeout uint32 `json:"timeout"`
+}
+
+func NewPathMTUAgingTimeoutOption(timeout uint32) Option {
+	return PathMTUAgingTimeoutOption{
+		Timeout: timeout,
+	}
+}
+
+func (o PathMTUAgingTimeoutOption) Code() OptionCode {
+	return OptionCodePathMTUAgingTimeout
+}
+
+func (o PathMTUAgingTimeoutOption) Encode() []byte {
+	return Uint32ToBytes(o.Timeout)
+}
+
+func (o PathMTUAgingTimeoutOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o PathMTUAgingTimeoutOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.Timeout = BytesToUint32(b)
+	return o, nil
+}
+
+func (o PathMTUAgingTimeoutOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Path MTU Aging Timeout: %d", o.Timeout))
+	return buf.String()
+}
@@ -0,0 +1,70 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option25 Path MTU Plateau Table Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-4.4
+// This option specifies a table of MTU sizes to use when performing
+// Path MTU Discovery as defined in RFC 1191.  The table is formatted as
+// a list of 16-bit unsigned integers, ordered from smallest to largest.
+// The minimum MTU value cannot be smaller than 68.
+//
+// The code for this option is 25.  Its minimum length is 2, and the
+// length MUST be a multiple of 2.
+//
+//	 Code   Len     Size 1      Size 2
+//	+-----+-----+-----+-----+-----+-----+---
+//	|  25 |  n  |  s1 |  s2 |  s1 |  s2 | ...
+//	+-----+-----+-----+-----+-----+-----+---
+type PathMTUPlateauTableOption struct {
+	Sizes []uint16 `json:"sizes"`
+}
+
+func NewPathMTUPlateauTableOption(sizes []uint16) Option {
+	return PathMTUPlateauTableOption{
+		Sizes: sizes,
+	}
+}
+
+func (o PathMTUPlateauTableOption) Code() OptionCode {
+	return OptionCodePathMTUPlateauTable
+}
+
+func (o PathMTUPlateauTableOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, size := range o.Sizes {
+		buf.Write(Uint16ToBytes(size))
+	}
+	return buf.Bytes()
+}
+
+func (o PathMTUPlateauTableOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o PathMTUPlateauTableOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 2); err != nil {
+		return o, err
+	}
+	o.Sizes = make([]uint16, 0, len(b)/2)
+	for i := 0; i < len(b); i += 2 {
+		size := BytesToUint16(b[i : i+2])
+		if size < 68 {
+			return o, fmt.Errorf("path MTU plateau %d below 68", size)
+		}
+		o.Sizes = append(o.Sizes, size)
+	}
+	return o, nil
+}
+
+func (o PathMTUPlateauTableOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Path MTU Plateau Table: %v", o.Sizes))
+	return buf.String()
+}
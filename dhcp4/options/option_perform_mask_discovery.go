@@ -0,0 +1,61 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option29 Perform Mask Discovery Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-5.4
+// This option specifies whether or not the client should perform subnet
+// mask discovery using ICMP. A value of 0 indicates that the client
+// should not perform mask discovery. A value of 1 means that the client
+// should perform mask discovery. The code for this option is 29, and its
+// length is 1.
+//
+//	 Code   Len   Value
+//	+-----+-----+-----+
+//	| 29  |  1  | 0/1 |
+//	+-----+-----+-----+
+type PerformMaskDiscoveryOption struct {
+	Enabled bool `json:"enabled"`
+}
+
+func NewPerformMaskDiscoveryOption(enabled bool) Option {
+	return PerformMaskDiscoveryOption{
+		Enabled: enabled,
+	}
+}
+
+func (o PerformMaskDiscoveryOption) Code() OptionCode {
+	return OptionCodePerformMaskDiscovery
+}
+
+func (o PerformMaskDiscoveryOption) Encode() []byte {
+	return boolToBytes(o.Enabled)
+}
+
+func (o PerformMaskDiscoveryOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o PerformMaskDiscoveryOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	value, err := bytesToBool(b)
+	if err != nil {
+		return o, err
+	}
+	o.Enabled = value
+	return o, nil
+}
+
+func (o PerformMaskDiscoveryOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Perform Mask Discovery: %t", o.Enabled))
+	return buf.String()
+}
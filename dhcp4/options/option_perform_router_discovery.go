@@ -0,0 +1,61 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option31 Perform Router Discovery Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-5.6
+// This option specifies whether or not the client should solicit routers
+// using the Router Discovery mechanism defined in RFC 1256. A value of 0
+// indicates that the client should not perform router discovery. A value
+// of 1 means that the client should perform router discovery. The code
+// for this option is 31, and its length is 1.
+//
+//	 Code   Len   Value
+//	+-----+-----+-----+
+//	| 31  |  1  | 0/1 |
+//	+-----+-----+-----+
+type PerformRouterDiscoveryOption struct {
+	Enabled bool `json:"enabled"`
+}
+
+func NewPerformRouterDiscoveryOption(enabled bool) Option {
+	return PerformRouterDiscoveryOption{
+		Enabled: enabled,
+	}
+}
+
+func (o PerformRouterDiscoveryOption) Code() OptionCode {
+	return OptionCodePerformRouterDiscovery
+}
+
+func (o PerformRouterDiscoveryOption) Encode() []byte {
+	return boolToBytes(o.Enabled)
+}
+
+func (o PerformRouterDiscoveryOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o PerformRouterDiscoveryOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	value, err := bytesToBool(b)
+	if err != nil {
+		return o, err
+	}
+	o.Enabled = value
+	return o, nil
+}
+
+func (o PerformRouterDiscoveryOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Perform Router Discovery: %t", o.Enabled))
+	return buf.String()
+}
@@ -0,0 +1,84 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// Option21 Policy Filter Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-4.3
+// This option specifies policy filters for non-local source routing.
+// The filters consist of a list of IP addresses and masks which specify
+// destination/mask pairs with which to filter incoming source routes.
+//
+// The code for this option is 21.  The minimum length of this option is
+// 8, and the length MUST be a multiple of 8.
+//
+//	 Code   Len         Address 1                  Mask 1
+//	+-----+-----+-----+-----+-----+-----+-----+-----+-----+-----+
+//	|  21 |  n  |  a1 |  a2 |  a3 |  a4 |  m1 |  m2 |  m3 |  m4 |
+//	+-----+-----+-----+-----+-----+-----+-----+-----+-----+-----+
+//	        Address 2                  Mask 2
+//	+-----+-----+-----+-----+-----+-----+-----+-----+---
+//	|  a1 |  a2 |  a3 |  a4 |  m1 |  m2 |  m3 |  m4 | ...
+//	+-----+-----+-----+-----+-----+-----+-----+-----+---
+type PolicyFilterOption struct {
+	Filters []net.IPNet
+}
+
+// NewPolicyFilterOption builds the option from CIDR strings such as
+// "10.0.0.0/8". Invalid entries are skipped.
+func NewPolicyFilterOption(filters []string) Option {
+	o := PolicyFilterOption{}
+	for _, filter := range filters {
+		if _, network, err := net.ParseCIDR(filter); err == nil {
+			o.Filters = append(o.Filters, *network)
+		}
+	}
+	return o
+}
+
+func (o PolicyFilterOption) Code() OptionCode {
+	return OptionCodePolicyFilter
+}
+
+func (o PolicyFilterOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, filter := range o.Filters {
+		buf.Write(filter.IP.To4())
+		buf.Write(net.IP(filter.Mask).To4())
+	}
+	return buf.Bytes()
+}
+
+func (o PolicyFilterOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o PolicyFilterOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 8); err != nil {
+		return o, err
+	}
+	o.Filters = nil
+	for i := 0; i < len(b); i += 8 {
+		o.Filters = append(o.Filters, net.IPNet{
+			IP:   net.IP(b[i : i+4]),
+			Mask: net.IPMask(b[i+4 : i+8]),
+		})
+	}
+	return o, nil
+}
+
+func (o PolicyFilterOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" Policy Filters:")
+	for _, filter := range o.Filters {
+		buf.WriteString(" ")
+		buf.WriteString(filter.String())
+	}
+	return buf.String()
+}
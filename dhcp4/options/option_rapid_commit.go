@@ -0,0 +1,50 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option80 Rapid Commit Option
+// https://www.rfc-editor.org/rfc/rfc4039#section-4
+// A client includes this option in a DHCPDISCOVER to ask for a two
+// message exchange.  A server that commits the lease at once answers
+// with a DHCPACK that includes the option.
+//
+//	 Code  Len
+//	+-----+-----+
+//	|  80 |  0  |
+//	+-----+-----+
+type RapidCommitOption struct{}
+
+func NewRapidCommitOption() Option {
+	return RapidCommitOption{}
+}
+
+func (o RapidCommitOption) Code() OptionCode {
+	return OptionCodeRapidCommit
+}
+
+func (o RapidCommitOption) Encode() []byte {
+	return []byte{}
+}
+
+func (o RapidCommitOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o RapidCommitOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 0); err != nil {
+		return o, err
+	}
+	return o, nil
+}
+
+func (o RapidCommitOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" Rapid Commit")
+	return buf.String()
+}
@@ -36,8 +36,16 @@ func (o RebindingTimeOption) Encode() []byte {
 }
 
 func (o RebindingTimeOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o RebindingTimeOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
 	o.RebindingTime = BytesToUint32(b)
-	return o
+	return o, nil
 }
 
 func (o RebindingTimeOption) String() string {
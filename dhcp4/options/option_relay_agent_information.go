@@ -0,0 +1,213 @@
+package options
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// RelayAgentSubOptionCode is a sub-option code of the relay agent
+// information option.
+type RelayAgentSubOptionCode uint8
+
+const (
+	RelayAgentCircuitID                RelayAgentSubOptionCode = 1  // https://www.rfc-editor.org/rfc/rfc3046#section-3.1
+	RelayAgentRemoteID                 RelayAgentSubOptionCode = 2  // https://www.rfc-editor.org/rfc/rfc3046#section-3.2
+	RelayAgentLinkSelection            RelayAgentSubOptionCode = 5  // https://www.rfc-editor.org/rfc/rfc3527
+	RelayAgentSubscriberID             RelayAgentSubOptionCode = 6  // https://www.rfc-editor.org/rfc/rfc3993
+	RelayAgentFlags                    RelayAgentSubOptionCode = 10 // https://www.rfc-editor.org/rfc/rfc5010
+	RelayAgentServerIdentifierOverride RelayAgentSubOptionCode = 11 // https://www.rfc-editor.org/rfc/rfc5107
+	RelayAgentRelayID                  RelayAgentSubOptionCode = 12 // https://www.rfc-editor.org/rfc/rfc6925
+)
+
+// RelayAgentFlagUnicast is set in the relay agent flags sub-option when
+// the relay received the client message as unicast.
+const RelayAgentFlagUnicast uint8 = 0x80
+
+// RelayAgentSubOption is one sub-option of the relay agent information
+// option.
+type RelayAgentSubOption struct {
+	Code RelayAgentSubOptionCode `json:"code"`
+	Data []byte                  `json:"data"`
+}
+
+func NewCircuitIDSubOption(id []byte) RelayAgentSubOption {
+	return RelayAgentSubOption{Code: RelayAgentCircuitID, Data: id}
+}
+
+func NewRemoteIDSubOption(id []byte) RelayAgentSubOption {
+	return RelayAgentSubOption{Code: RelayAgentRemoteID, Data: id}
+}
+
+// NewRelayIDSubOption identifies the relay agent itself, so that a bulk
+// leasequery can ask for every lease it relayed (RFC 6925).
+func NewRelayIDSubOption(id []byte) RelayAgentSubOption {
+	return RelayAgentSubOption{Code: RelayAgentRelayID, Data: id}
+}
+
+func NewLinkSelectionSubOption(subnet string) RelayAgentSubOption {
+	return RelayAgentSubOption{Code: RelayAgentLinkSelection, Data: net.ParseIP(subnet).To4()}
+}
+
+func NewSubscriberIDSubOption(id string) RelayAgentSubOption {
+	return RelayAgentSubOption{Code: RelayAgentSubscriberID, Data: []byte(id)}
+}
+
+func NewRelayAgentFlagsSubOption(flags uint8) RelayAgentSubOption {
+	return RelayAgentSubOption{Code: RelayAgentFlags, Data: []byte{flags}}
+}
+
+func NewServerIdentifierOverrideSubOption(server string) RelayAgentSubOption {
+	return RelayAgentSubOption{Code: RelayAgentServerIdentifierOverride, Data: net.ParseIP(server).To4()}
+}
+
+// Option82 Relay Agent Information Option
+// https://www.rfc-editor.org/rfc/rfc3046
+// This option is inserted by a relay agent when forwarding client
+// messages to a server.  Servers recognizing the option echo it
+// verbatim as the last option of their reply.
+//
+// The option is organized as a single option that contains one or more
+// sub-options that convey information known by the relay agent.
+//
+//	 Code   Len     Agent Information Field
+//	+------+------+------+------+------+------+--...-+------+
+//	|  82  |   N  |  i1  |  i2  |  i3  |  i4  |      |  iN  |
+//	+------+------+------+------+------+------+--...-+------+
+//
+//	 SubOpt  Len     Sub-option Value
+//	+------+------+------+------+------+------+--...-+------+
+//	|  1   |   N  |  s1  |  s2  |  s3  |  s4  |      |  sN  |
+//	+------+------+------+------+------+------+--...-+------+
+type RelayAgentInformationOption struct {
+	SubOptions []RelayAgentSubOption `json:"sub_options"`
+}
+
+func NewRelayAgentInformationOption(subOptions ...RelayAgentSubOption) Option {
+	return RelayAgentInformationOption{
+		SubOptions: subOptions,
+	}
+}
+
+func (o RelayAgentInformationOption) Code() OptionCode {
+	return OptionCodeRelayAgentInformation
+}
+
+func (o RelayAgentInformationOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, subOption := range o.SubOptions {
+		buf.WriteByte(byte(subOption.Code))
+		buf.WriteByte(byte(len(subOption.Data)))
+		buf.Write(subOption.Data)
+	}
+	return buf.Bytes()
+}
+
+func (o RelayAgentInformationOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o RelayAgentInformationOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 2); err != nil {
+		return o, err
+	}
+	o.SubOptions = nil
+	for len(b) > 0 {
+		if len(b) < 2 || len(b) < 2+int(b[1]) {
+			return o, errors.New("truncated relay agent sub-option")
+		}
+		subOption := RelayAgentSubOption{
+			Code: RelayAgentSubOptionCode(b[0]),
+			Data: b[2 : 2+int(b[1])],
+		}
+		var err error
+		switch subOption.Code {
+		case RelayAgentCircuitID, RelayAgentRemoteID, RelayAgentSubscriberID:
+			err = checkMinLength(subOption.Data, 1)
+		case RelayAgentLinkSelection, RelayAgentServerIdentifierOverride:
+			err = checkLength(subOption.Data, 4)
+		case RelayAgentFlags:
+			err = checkLength(subOption.Data, 1)
+		}
+		if err != nil {
+			return o, fmt.Errorf("sub-option %d: %w", subOption.Code, err)
+		}
+		o.SubOptions = append(o.SubOptions, subOption)
+		b = b[2+int(b[1]):]
+	}
+	return o, nil
+}
+
+// Get returns the data of the first sub-option with the given code.
+func (o RelayAgentInformationOption) Get(code RelayAgentSubOptionCode) ([]byte, bool) {
+	for _, subOption := range o.SubOptions {
+		if subOption.Code == code {
+			return subOption.Data, true
+		}
+	}
+	return nil, false
+}
+
+func (o RelayAgentInformationOption) CircuitID() []byte {
+	id, _ := o.Get(RelayAgentCircuitID)
+	return id
+}
+
+func (o RelayAgentInformationOption) RemoteID() []byte {
+	id, _ := o.Get(RelayAgentRemoteID)
+	return id
+}
+
+// RelayID returns the relay-id sub-option (RFC 6925), or nil.
+func (o RelayAgentInformationOption) RelayID() []byte {
+	id, _ := o.Get(RelayAgentRelayID)
+	return id
+}
+
+// LinkSelection returns the subnet address the relay asked the server to
+// allocate from, or nil.
+func (o RelayAgentInformationOption) LinkSelection() net.IP {
+	return o.getIP(RelayAgentLinkSelection)
+}
+
+func (o RelayAgentInformationOption) SubscriberID() string {
+	id, _ := o.Get(RelayAgentSubscriberID)
+	return string(id)
+}
+
+// ServerIdentifierOverride returns the address the client should use as
+// server identifier in place of the server's own, or nil.
+func (o RelayAgentInformationOption) ServerIdentifierOverride() net.IP {
+	return o.getIP(RelayAgentServerIdentifierOverride)
+}
+
+// Flags returns the relay agent flags and whether they were present.
+func (o RelayAgentInformationOption) Flags() (uint8, bool) {
+	flags, ok := o.Get(RelayAgentFlags)
+	if !ok || len(flags) != 1 {
+		return 0, false
+	}
+	return flags[0], true
+}
+
+func (o RelayAgentInformationOption) getIP(code RelayAgentSubOptionCode) net.IP {
+	ip, ok := o.Get(code)
+	if !ok || len(ip) != net.IPv4len {
+		return nil
+	}
+	return net.IP(ip)
+}
+
+func (o RelayAgentInformationOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" Relay Agent Information:")
+	for _, subOption := range o.SubOptions {
+		buf.WriteString(fmt.Sprintf(" %d=%s", subOption.Code, hex.EncodeToString(subOption.Data)))
+	}
+	return buf.String()
+}
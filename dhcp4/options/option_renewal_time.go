@@ -37,8 +37,16 @@ func (o RenewalTimeOption) Encode() []byte {
 }
 
 func (o RenewalTimeOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o RenewalTimeOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
 	o.RenewalTime = BytesToUint32(b)
-	return o
+	return o, nil
 }
 
 func (o RenewalTimeOption) String() string {
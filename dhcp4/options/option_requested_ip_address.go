@@ -36,8 +36,16 @@ func (o RequestedIPAddressOption) Encode() []byte {
 }
 
 func (o RequestedIPAddressOption) Decode(b []byte) Option {
-	o.Address = b
-	return o
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o RequestedIPAddressOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.Address = net.IP(b)
+	return o, nil
 }
 
 func (o RequestedIPAddressOption) String() string {
@@ -0,0 +1,56 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option17 Root Path
+// https://www.rfc-editor.org/rfc/rfc2132#section-3.19
+// This option specifies the path-name that contains the client's root
+// disk. The path is formatted as a character string consisting of
+// characters from the NVT ASCII character set. The code for this option
+// is 17. Its minimum length is 1.
+//
+//	 Code   Len   Root Disk Pathname
+//	+-----+-----+-----+-----+-----+-----+--
+//	| 17  |  n  | n1  | n2  | n3  | n4  |  ...
+//	+-----+-----+-----+-----+-----+-----+--
+type RootPathOption struct {
+	RootPath string `json:"path"`
+}
+
+func NewRootPathOption(path string) Option {
+	return RootPathOption{
+		RootPath: path,
+	}
+}
+
+func (o RootPathOption) Code() OptionCode {
+	return OptionCodeRootPath
+}
+
+func (o RootPathOption) Encode() []byte {
+	return []byte(o.RootPath)
+}
+
+func (o RootPathOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o RootPathOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.RootPath = string(b)
+	return o, nil
+}
+
+func (o RootPathOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Root Disk Pathname: %s", o.RootPath))
+	return buf.String()
+}
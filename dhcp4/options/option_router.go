@@ -41,10 +41,16 @@ func (o RouterOption) Encode() []byte {
 }
 
 func (o RouterOption) Decode(b []byte) Option {
-	for i := 0; i < len(b); i += 4 {
-		o.Routers = append(o.Routers, b[i:i+4])
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o RouterOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 4); err != nil {
+		return o, err
 	}
-	return o
+	o.Routers = decodeIPs(b)
+	return o, nil
 }
 
 func (o RouterOption) String() string {
@@ -0,0 +1,56 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// Option32 Router Solicitation Address Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-5.7
+// This option specifies the address to which the client should transmit
+// router solicitation requests. The code for this option is 32, and its
+// length is 4.
+//
+//	 Code   Len   Address
+//	+-----+-----+-----+-----+-----+-----+
+//	| 32  |  4  | a1  | a2  | a3  | a4  |
+//	+-----+-----+-----+-----+-----+-----+
+type RouterSolicitationAddressOption struct {
+	Address net.IP
+}
+
+func NewRouterSolicitationAddressOption(address string) Option {
+	return RouterSolicitationAddressOption{
+		Address: net.ParseIP(address),
+	}
+}
+
+func (o RouterSolicitationAddressOption) Code() OptionCode {
+	return OptionCodeRouterSolicitationAddress
+}
+
+func (o RouterSolicitationAddressOption) Encode() []byte {
+	return o.Address.To4()
+}
+
+func (o RouterSolicitationAddressOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o RouterSolicitationAddressOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.Address = net.IP(b)
+	return o, nil
+}
+
+func (o RouterSolicitationAddressOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Router Solicitation Address: %s", o.Address.String()))
+	return buf.String()
+}
@@ -32,8 +32,16 @@ func (o ServerIdentifierOption) Encode() []byte {
 }
 
 func (o ServerIdentifierOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o ServerIdentifierOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
 	o.ServerIdentifier = net.IPv4(b[0], b[1], b[2], b[3])
-	return o
+	return o, nil
 }
 
 func (o ServerIdentifierOption) String() string {
@@ -0,0 +1,105 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// Option33 Static Route Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-5.8
+// This option specifies a list of static routes that the client should
+// install in its routing cache.  If multiple routes to the same
+// destination are specified, they are listed in descending order of
+// priority.
+//
+// The routes consist of a list of IP address pairs.  The first address
+// is the destination address, and the second address is the router for
+// the destination.
+//
+// The default route (0.0.0.0) is an illegal destination for a static
+// route.  See section 3.5 for information about the router option.
+//
+// The code for this option is 33.  The minimum length of this option is
+// 8, and the length MUST be a multiple of 8.
+//
+//	 Code   Len         Destination 1           Router 1
+//	+-----+-----+-----+-----+-----+-----+-----+-----+-----+-----+
+//	|  33 |  n  |  d1 |  d2 |  d3 |  d4 |  r1 |  r2 |  r3 |  r4 |
+//	+-----+-----+-----+-----+-----+-----+-----+-----+-----+-----+
+//	        Destination 2           Router 2
+//	+-----+-----+-----+-----+-----+-----+-----+-----+---
+//	|  d1 |  d2 |  d3 |  d4 |  r1 |  r2 |  r3 |  r4 | ...
+//	+-----+-----+-----+-----+-----+-----+-----+-----+---
+type StaticRouteOption struct {
+	Routes []StaticRoute
+}
+
+// StaticRoute is a classful destination and the router to reach it.
+type StaticRoute struct {
+	Destination net.IP
+	Router      net.IP
+}
+
+// NewStaticRoute returns the route to destination through router.
+func NewStaticRoute(destination, router string) StaticRoute {
+	return StaticRoute{
+		Destination: net.ParseIP(destination),
+		Router:      net.ParseIP(router),
+	}
+}
+
+// NewStaticRouteOption builds the option from routes in descending order
+// of priority.
+func NewStaticRouteOption(routes []StaticRoute) Option {
+	return StaticRouteOption{
+		Routes: routes,
+	}
+}
+
+func (o StaticRouteOption) Code() OptionCode {
+	return OptionCodeStaticRoute
+}
+
+func (o StaticRouteOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, route := range o.Routes {
+		buf.Write(route.Destination.To4())
+		buf.Write(route.Router.To4())
+	}
+	return buf.Bytes()
+}
+
+func (o StaticRouteOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o StaticRouteOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 8); err != nil {
+		return o, err
+	}
+	o.Routes = nil
+	for i := 0; i < len(b); i += 8 {
+		route := StaticRoute{
+			Destination: net.IP(b[i : i+4]),
+			Router:      net.IP(b[i+4 : i+8]),
+		}
+		if route.Destination.Equal(net.IPv4zero) {
+			return o, fmt.Errorf("default route is not a legal static route")
+		}
+		o.Routes = append(o.Routes, route)
+	}
+	return o, nil
+}
+
+func (o StaticRouteOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" Static Routes:")
+	for _, route := range o.Routes {
+		buf.WriteString(fmt.Sprintf(" %s via %s", route.Destination, route.Router))
+	}
+	return buf.String()
+}
@@ -0,0 +1,87 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// StatusCode is the status carried in the status-code option.
+type StatusCode uint8
+
+// Status codes of RFC 6926 section 6.2.2.
+const (
+	StatusSuccess         StatusCode = 0
+	StatusUnspecFail      StatusCode = 1
+	StatusQueryTerminated StatusCode = 2
+	StatusMalformedQuery  StatusCode = 3
+	StatusNotAllowed      StatusCode = 4
+)
+
+func (s StatusCode) String() string {
+	switch s {
+	case StatusSuccess:
+		return "Success"
+	case StatusUnspecFail:
+		return "UnspecFail"
+	case StatusQueryTerminated:
+		return "QueryTerminated"
+	case StatusMalformedQuery:
+		return "MalformedQuery"
+	case StatusNotAllowed:
+		return "NotAllowed"
+	default:
+		return fmt.Sprintf("Status(%d)", uint8(s))
+	}
+}
+
+// Option151 Status Code
+// https://www.rfc-editor.org/rfc/rfc6926#section-6.2.2
+// This option reports the outcome of a bulk leasequery in a
+// DHCPLEASEQUERYSTATUS or DHCPLEASEQUERYDONE message.  The status
+// message is optional UTF-8 text.
+//
+//	 Code   Len  Status  Status Message
+//	+-----+-----+-------+-----+-----+--
+//	| 151 |  n  |  code |  s1 |  s2 |  ...
+//	+-----+-----+-------+-----+-----+--
+type StatusCodeOption struct {
+	Status  StatusCode `json:"status"`
+	Message string     `json:"message"`
+}
+
+func NewStatusCodeOption(status StatusCode, message string) Option {
+	return StatusCodeOption{Status: status, Message: message}
+}
+
+func (o StatusCodeOption) Code() OptionCode {
+	return OptionCodeStatusCode
+}
+
+func (o StatusCodeOption) Encode() []byte {
+	return append([]byte{byte(o.Status)}, o.Message...)
+}
+
+func (o StatusCodeOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o StatusCodeOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.Status = StatusCode(b[0])
+	o.Message = string(b[1:])
+	return o, nil
+}
+
+func (o StatusCodeOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Status: %s", o.Status))
+	if o.Message != "" {
+		buf.WriteString(fmt.Sprintf(", Message: %s", o.Message))
+	}
+	return buf.String()
+}
@@ -34,8 +34,16 @@ func (o SubnetMaskOption) Encode() []byte {
 }
 
 func (o SubnetMaskOption) Decode(b []byte) Option {
-	o.SubnetMask = b
-	return o
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o SubnetMaskOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.SubnetMask = net.IP(b)
+	return o, nil
 }
 
 func (o SubnetMaskOption) String() string {
@@ -0,0 +1,56 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// Option118 Subnet Selection
+// https://www.rfc-editor.org/rfc/rfc3011#section-3
+// This option allows the DHCP client to specify the subnet on which to
+// allocate an address.  It takes precedence over the giaddr field, and
+// a server that supports it returns an identical copy to the client.
+//
+//	 Code   Len        IPv4 Address
+//	+-----+-----+-----+-----+-----+-----+
+//	| 118 |  4  |  A1 |  A2 |  A3 |  A4 |
+//	+-----+-----+-----+-----+-----+-----+
+type SubnetSelectionOption struct {
+	Subnet net.IP `json:"subnet"`
+}
+
+func NewSubnetSelectionOption(subnet string) Option {
+	return SubnetSelectionOption{
+		Subnet: net.ParseIP(subnet),
+	}
+}
+
+func (o SubnetSelectionOption) Code() OptionCode {
+	return OptionCodeSubnetSelection
+}
+
+func (o SubnetSelectionOption) Encode() []byte {
+	return o.Subnet.To4()
+}
+
+func (o SubnetSelectionOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o SubnetSelectionOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.Subnet = net.IP(b)
+	return o, nil
+}
+
+func (o SubnetSelectionOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Subnet: %s", o.Subnet))
+	return buf.String()
+}
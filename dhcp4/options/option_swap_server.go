@@ -0,0 +1,55 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// Option16 Swap Server
+// https://www.rfc-editor.org/rfc/rfc2132#section-3.18
+// This specifies the IP address of the client's swap server. The code
+// for this option is 16 and its length is 4.
+//
+//	 Code   Len   Address
+//	+-----+-----+-----+-----+-----+-----+
+//	| 16  |  4  | a1  | a2  | a3  | a4  |
+//	+-----+-----+-----+-----+-----+-----+
+type SwapServerOption struct {
+	SwapServer net.IP
+}
+
+func NewSwapServerOption(address string) Option {
+	return SwapServerOption{
+		SwapServer: net.ParseIP(address),
+	}
+}
+
+func (o SwapServerOption) Code() OptionCode {
+	return OptionCodeSwapServer
+}
+
+func (o SwapServerOption) Encode() []byte {
+	return o.SwapServer.To4()
+}
+
+func (o SwapServerOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o SwapServerOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.SwapServer = net.IP(b)
+	return o, nil
+}
+
+func (o SwapServerOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Swap Server: %s", o.SwapServer.String()))
+	return buf.String()
+}
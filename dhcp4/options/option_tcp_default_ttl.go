@@ -0,0 +1,59 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option37 TCP Default TTL Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-7.1
+// This option specifies the default TTL that the client should use when
+// sending TCP segments. The value is represented as an 8-bit unsigned
+// integer. The minimum value is 1. The code for this option is 37, and
+// its length is 1.
+//
+//	 Code   Len   TCP Default TTL
+//	+-----+-----+-----+
+//	| 37  |  1  |  v  |
+//	+-----+-----+-----+
+type TCPDefaultTTLOption struct {
+	TTL uint8 `json:"ttl"`
+}
+
+func NewTCPDefaultTTLOption(ttl uint8) Option {
+	return TCPDefaultTTLOption{
+		TTL: ttl,
+	}
+}
+
+func (o TCPDefaultTTLOption) Code() OptionCode {
+	return OptionCodeTcpDefaultTTL
+}
+
+func (o TCPDefaultTTLOption) Encode() []byte {
+	return Uint8ToBytes(o.TTL)
+}
+
+func (o TCPDefaultTTLOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o TCPDefaultTTLOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	if b[0] == 0 {
+		return o, fmt.Errorf("invalid time-to-live %d", b[0])
+	}
+	o.TTL = b[0]
+	return o, nil
+}
+
+func (o TCPDefaultTTLOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" TCP Default TTL: %d", o.TTL))
+	return buf.String()
+}
@@ -0,0 +1,61 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option39 TCP Keepalive Garbage Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-7.3
+// This option specifies the whether or not the client should send TCP
+// keepalive messages with an octet of garbage for compatibility with
+// older implementations. A value of 0 indicates that a garbage octet
+// should not be sent. A value of 1 indicates that a garbage octet should
+// be sent. The code for this option is 39, and its length is 1.
+//
+//	 Code   Len   Value
+//	+-----+-----+-----+
+//	| 39  |  1  | 0/1 |
+//	+-----+-----+-----+
+type TCPKeepaliveGarbageOption struct {
+	Enabled bool `json:"enabled"`
+}
+
+func NewTCPKeepaliveGarbageOption(enabled bool) Option {
+	return TCPKeepaliveGarbageOption{
+		Enabled: enabled,
+	}
+}
+
+func (o TCPKeepaliveGarbageOption) Code() OptionCode {
+	return OptionCodeTcpKeepaliveGarbage
+}
+
+func (o TCPKeepaliveGarbageOption) Encode() []byte {
+	return boolToBytes(o.Enabled)
+}
+
+func (o TCPKeepaliveGarbageOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o TCPKeepaliveGarbageOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	value, err := bytesToBool(b)
+	if err != nil {
+		return o, err
+	}
+	o.Enabled = value
+	return o, nil
+}
+
+func (o TCPKeepaliveGarbageOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" TCP Keepalive Garbage: %t", o.Enabled))
+	return buf.String()
+}
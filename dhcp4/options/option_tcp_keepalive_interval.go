@@ -0,0 +1,58 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option38 TCP Keepalive Interval Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-7.2
+// This option specifies the interval (in seconds) that the client TCP
+// should wait before sending a keepalive message on a TCP connection.
+// The time is specified as a 32-bit unsigned integer. A value of zero
+// indicates that the client should not generate keepalive messages on
+// connections unless specifically requested by an application. The code
+// for this option is 38, and its length is 4.
+//
+//	 Code   Len   TCP Keepalive Interval
+//	+-----+-----+-----+-----+-----+-----+
+//	| 38  |  4  | t1  | t2  | t3  | t4  |
+//	+-----+-----+-----+-----+-----+-----+
+type TCPKeepaliveIntervalOption struct {
+	Interval uint32 `json:"interval"`
+}
+
+func NewTCPKeepaliveIntervalOption(interval uint32) Option {
+	return TCPKeepaliveIntervalOption{
+		Interval: interval,
+	}
+}
+
+func (o TCPKeepaliveIntervalOption) Code() OptionCode {
+	return OptionCodeTcpKeepaliveInterval
+}
+
+func (o TCPKeepaliveIntervalOption) Encode() []byte {
+	return Uint32ToBytes(o.Interval)
+}
+
+func (o TCPKeepaliveIntervalOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o TCPKeepaliveIntervalOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.Interval = BytesToUint32(b)
+	return o, nil
+}
+
+func (o TCPKeepaliveIntervalOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" TCP Keepalive Interval: %d", o.Interval))
+	return buf.String()
+}
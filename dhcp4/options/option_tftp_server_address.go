@@ -0,0 +1,61 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// Option150 TFTP Server Address
+// https://www.rfc-editor.org/rfc/rfc5859#section-3
+// This option carries the IPv4 addresses of one or more TFTP servers,
+// in order of preference. Its length is a non-zero multiple of 4.
+//
+//	 Code   Len   TFTP Server IPv4 Address(es)
+//	+-----+-----+-----+-----+-----+-----+-----+-----+--
+//	| 150 |  n  | a1  | a2  | a3  | a4  | a1  | a2  |  ...
+//	+-----+-----+-----+-----+-----+-----+-----+-----+--
+type TFTPServerAddressOption struct {
+	Servers []net.IP
+}
+
+func NewTFTPServerAddressOption(servers []string) Option {
+	o := TFTPServerAddressOption{}
+	for _, server := range servers {
+		o.Servers = append(o.Servers, net.ParseIP(server))
+	}
+	return o
+}
+
+func (o TFTPServerAddressOption) Code() OptionCode {
+	return OptionCodeTFTPServerAddress
+}
+
+func (o TFTPServerAddressOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, server := range o.Servers {
+		buf.Write(server.To4())
+	}
+	return buf.Bytes()
+}
+
+func (o TFTPServerAddressOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o TFTPServerAddressOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMultiple(b, 4); err != nil {
+		return o, err
+	}
+	o.Servers = decodeIPs(b)
+	return o, nil
+}
+
+func (o TFTPServerAddressOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" TFTP Servers: %v", o.Servers))
+	return buf.String()
+}
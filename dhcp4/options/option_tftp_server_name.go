@@ -0,0 +1,56 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option66 TFTP server name
+// https://www.rfc-editor.org/rfc/rfc2132#section-9.4
+// This option is used to identify a TFTP server when the 'sname' field
+// in the DHCP header has been used for DHCP options.
+//
+// The code for this option is 66, and its minimum length is 1.
+//
+//	 Code  Len   TFTP server
+//	+-----+-----+-----+-----+-----+---
+//	| 66  |  n  |  c1 |  c2 |  c3 | ...
+//	+-----+-----+-----+-----+-----+---
+type TFTPServerNameOption struct {
+	ServerName string `json:"server_name"`
+}
+
+func NewTFTPServerNameOption(serverName string) Option {
+	return TFTPServerNameOption{
+		ServerName: serverName,
+	}
+}
+
+func (o TFTPServerNameOption) Code() OptionCode {
+	return OptionCodeTFTPServerName
+}
+
+func (o TFTPServerNameOption) Encode() []byte {
+	return []byte(o.ServerName)
+}
+
+func (o TFTPServerNameOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o TFTPServerNameOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.ServerName = string(b)
+	return o, nil
+}
+
+func (o TFTPServerNameOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" TFTP Server: %s", o.ServerName))
+	return buf.String()
+}
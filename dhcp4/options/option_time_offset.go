@@ -1 +1,59 @@
 package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option2 Time Offset
+// https://www.rfc-editor.org/rfc/rfc2132#section-3.4
+// The time offset field specifies the offset of the client's subnet in
+// seconds from Coordinated Universal Time (UTC).  The offset is
+// expressed as a two's complement 32-bit integer.  A positive offset
+// indicates a location east of the zero meridian and a negative offset
+// indicates a location west of the zero meridian.
+//
+// The code for the time offset option is 2, and its length is 4 octets.
+//
+//	 Code   Len        Time Offset
+//	+-----+-----+-----+-----+-----+-----+
+//	|  2  |  4  |  n1 |  n2 |  n3 |  n4 |
+//	+-----+-----+-----+-----+-----+-----+
+type TimeOffsetOption struct {
+	Offset int32 `json:"offset"`
+}
+
+func NewTimeOffsetOption(offset int32) Option {
+	return TimeOffsetOption{
+		Offset: offset,
+	}
+}
+
+func (o TimeOffsetOption) Code() OptionCode {
+	return OptionCodeTimeOffset
+}
+
+func (o TimeOffsetOption) Encode() []byte {
+	return Uint32ToBytes(uint32(o.Offset))
+}
+
+func (o TimeOffsetOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o TimeOffsetOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 4); err != nil {
+		return o, err
+	}
+	o.Offset = int32(BytesToUint32(b))
+	return o, nil
+}
+
+func (o TimeOffsetOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Time Offset: %d", o.Offset))
+	return buf.String()
+}
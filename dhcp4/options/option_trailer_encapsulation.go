@@ -0,0 +1,61 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option34 Trailer Encapsulation Option
+// https://www.rfc-editor.org/rfc/rfc2132#section-6.1
+// This option specifies whether or not the client should negotiate the
+// use of trailers (RFC 893) when using the ARP protocol. A value of 0
+// indicates that the client should not attempt to use trailers. A value
+// of 1 means that the client should attempt to use trailers. The code
+// for this option is 34, and its length is 1.
+//
+//	 Code   Len   Value
+//	+-----+-----+-----+
+//	| 34  |  1  | 0/1 |
+//	+-----+-----+-----+
+type TrailerEncapsulationOption struct {
+	Enabled bool `json:"enabled"`
+}
+
+func NewTrailerEncapsulationOption(enabled bool) Option {
+	return TrailerEncapsulationOption{
+		Enabled: enabled,
+	}
+}
+
+func (o TrailerEncapsulationOption) Code() OptionCode {
+	return OptionCodeTrailerEncapsulation
+}
+
+func (o TrailerEncapsulationOption) Encode() []byte {
+	return boolToBytes(o.Enabled)
+}
+
+func (o TrailerEncapsulationOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o TrailerEncapsulationOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkLength(b, 1); err != nil {
+		return o, err
+	}
+	value, err := bytesToBool(b)
+	if err != nil {
+		return o, err
+	}
+	o.Enabled = value
+	return o, nil
+}
+
+func (o TrailerEncapsulationOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Trailer Encapsulation: %t", o.Enabled))
+	return buf.String()
+}
@@ -0,0 +1,100 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Option77 User Class
+// https://www.rfc-editor.org/rfc/rfc3004#section-4
+// This option is used by a DHCP client to optionally identify the type
+// or category of user or applications it represents.  It carries one or
+// more user class instances, each prefixed by its length.
+//
+//	 Code   Len   Value
+//	+-----+-----+---------------------  . . .  --+
+//	| 77  |  N  | User Class Data ('Len' octets)  |
+//	+-----+-----+---------------------  . . .  --+
+//
+//	UC_Len_i     User_Class_Data_i
+//	+--------+------------------------  . . .  --+
+//	|  L_i   | Opaque-Data ('UC_Len_i' octets)    |
+//	+--------+------------------------  . . .  --+
+//
+// Some clients, Windows among them, send a single class without the
+// length prefix. Payloads that are not a valid sequence of instances
+// decode as one class holding the whole payload, and Raw is set so the
+// option re-encodes unchanged.
+type UserClassOption struct {
+	Classes [][]byte `json:"classes"`
+	Raw     bool     `json:"raw,omitempty"`
+}
+
+func NewUserClassOption(classes ...string) Option {
+	o := UserClassOption{}
+	for _, class := range classes {
+		o.Classes = append(o.Classes, []byte(class))
+	}
+	return o
+}
+
+func (o UserClassOption) Code() OptionCode {
+	return OptionCodeUserClass
+}
+
+func (o UserClassOption) Encode() []byte {
+	if o.Raw && len(o.Classes) == 1 {
+		return o.Classes[0]
+	}
+	var buf bytes.Buffer
+	for _, class := range o.Classes {
+		buf.WriteByte(byte(len(class)))
+		buf.Write(class)
+	}
+	return buf.Bytes()
+}
+
+func (o UserClassOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o UserClassOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.Classes, o.Raw = nil, false
+	for data := b; len(data) > 0; {
+		n := int(data[0])
+		if n == 0 || len(data) < 1+n {
+			o.Classes, o.Raw = [][]byte{b}, true
+			return o, nil
+		}
+		o.Classes = append(o.Classes, data[1:1+n])
+		data = data[1+n:]
+	}
+	return o, nil
+}
+
+// Has reports whether class is one of the user classes.
+func (o UserClassOption) Has(class string) bool {
+	for _, c := range o.Classes {
+		if string(c) == class {
+			return true
+		}
+	}
+	return false
+}
+
+func (o UserClassOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	classes := make([]string, 0, len(o.Classes))
+	for _, class := range o.Classes {
+		classes = append(classes, fmt.Sprintf("%q", class))
+	}
+	buf.WriteString(fmt.Sprintf(" User Classes: %s", strings.Join(classes, ", ")))
+	return buf.String()
+}
@@ -0,0 +1,60 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Option60 Vendor class identifier
+// https://www.rfc-editor.org/rfc/rfc2132#section-9.13
+// This option is used by DHCP clients to optionally identify the vendor
+// type and configuration of a DHCP client.  The information is a string
+// of n octets, interpreted by servers.  Servers not equipped to
+// interpret the class-specific information sent by a client MUST ignore
+// it (although it may be reported).  Servers that respond SHOULD only
+// use option 43 to return the vendor-specific information to the client.
+//
+// The code for this option is 60, and its minimum length is 1.
+//
+//	 Code   Len   Vendor class Identifier
+//	+-----+-----+-----+-----+---
+//	|  60 |  n  |  i1 |  i2 | ...
+//	+-----+-----+-----+-----+---
+type VendorClassIdentifierOption struct {
+	VendorClass string `json:"vendor_class"`
+}
+
+func NewVendorClassIdentifierOption(vendorClass string) Option {
+	return VendorClassIdentifierOption{
+		VendorClass: vendorClass,
+	}
+}
+
+func (o VendorClassIdentifierOption) Code() OptionCode {
+	return OptionCodeVendorClassIdentifier
+}
+
+func (o VendorClassIdentifierOption) Encode() []byte {
+	return []byte(o.VendorClass)
+}
+
+func (o VendorClassIdentifierOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o VendorClassIdentifierOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.VendorClass = string(b)
+	return o, nil
+}
+
+func (o VendorClassIdentifierOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(fmt.Sprintf(" Vendor Class: %s", o.VendorClass))
+	return buf.String()
+}
@@ -0,0 +1,215 @@
+package options
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	vendorMu         sync.RWMutex
+	vendorSubOptions = map[string]map[OptionCode]Option{}
+)
+
+// RegisterVendorSubOption registers option as the type used to decode
+// the option 43 sub-option with its code for clients whose vendor class
+// identifier starts with vendorClass. The longest registered prefix wins,
+// so "PXEClient" covers "PXEClient:Arch:00007:UNDI:003016".
+func RegisterVendorSubOption(vendorClass string, option Option) {
+	vendorMu.Lock()
+	defer vendorMu.Unlock()
+	subOptions, ok := vendorSubOptions[vendorClass]
+	if !ok {
+		subOptions = make(map[OptionCode]Option)
+		vendorSubOptions[vendorClass] = subOptions
+	}
+	subOptions[option.Code()] = option
+}
+
+// lookupVendorSubOptions returns the sub-option types registered for the
+// longest prefix of vendorClass.
+func lookupVendorSubOptions(vendorClass string) (map[OptionCode]Option, bool) {
+	vendorMu.RLock()
+	defer vendorMu.RUnlock()
+	var found map[OptionCode]Option
+	longest := -1
+	for prefix, subOptions := range vendorSubOptions {
+		if strings.HasPrefix(vendorClass, prefix) && len(prefix) > longest {
+			found, longest = subOptions, len(prefix)
+		}
+	}
+	return found, longest >= 0
+}
+
+// Option43 Vendor Specific Information
+// https://www.rfc-editor.org/rfc/rfc2132#section-8.4
+// This option is used by clients and servers to exchange vendor-specific
+// information.  The definition of this information is vendor specific.
+// The vendor is indicated in the vendor class identifier option.
+//
+// When encapsulated vendor-specific extensions are used, the information
+// is a series of sub-options in the same code/length/data format as DHCP
+// options, and the codes are defined by the vendor.
+//
+// The code for this option is 43 and its minimum length is 1.
+//
+//	Code   Len   Vendor-specific information
+//	+-----+-----+-----+-----+---
+//	|  43 |  n  |  i1 |  i2 | ...
+//	+-----+-----+-----+-----+---
+//
+//	Code   Len   Data item        Code   Len   Data item       Code
+//	+-----+-----+-----+-----+---+-----+-----+-----+-----+---+-----+
+//	|  T1 |  n  |  d1 |  d2 |...|  T2 |  n  |  D1 |  D2 |...| ... |
+//	+-----+-----+-----+-----+---+-----+-----+-----+-----+---+-----+
+//
+// Decoding keeps the raw payload in Data because its format depends on
+// the vendor class; DecodeVendor parses it into SubOptions.
+type VendorSpecificInformationOption struct {
+	VendorClass string  `json:"vendor_class,omitempty"`
+	SubOptions  Options `json:"sub_options,omitempty"`
+	Data        []byte  `json:"data,omitempty"`
+}
+
+// NewVendorSpecificInformationOption builds an encapsulated payload from
+// sub-options. Sub-options with more than 255 octets of data are left
+// out when the option is encoded; call Validate on the result to find
+// them.
+func NewVendorSpecificInformationOption(subOptions ...Option) Option {
+	return VendorSpecificInformationOption{
+		SubOptions: subOptions,
+	}
+}
+
+// NewRawVendorSpecificInformationOption carries an opaque payload.
+func NewRawVendorSpecificInformationOption(data []byte) Option {
+	return VendorSpecificInformationOption{
+		Data: data,
+	}
+}
+
+func (o VendorSpecificInformationOption) Code() OptionCode {
+	return OptionCodeVendorSpecificInformation
+}
+
+func (o VendorSpecificInformationOption) Encode() []byte {
+	if len(o.SubOptions) == 0 {
+		return o.Data
+	}
+	var buf bytes.Buffer
+	_ = encodeSubOptions(&buf, o.SubOptions)
+	return buf.Bytes()
+}
+
+// Validate reports a sub-option too long to be encoded. Encode leaves
+// such sub-options out.
+func (o VendorSpecificInformationOption) Validate() error {
+	var buf bytes.Buffer
+	return encodeSubOptions(&buf, o.SubOptions)
+}
+
+func (o VendorSpecificInformationOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o VendorSpecificInformationOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 1); err != nil {
+		return o, err
+	}
+	o.Data = b
+	o.SubOptions = nil
+	o.VendorClass = ""
+	return o, nil
+}
+
+// DecodeVendor parses the payload as encapsulated sub-options using the
+// types registered for vendorClass. Sub-options without a registered
+// type decode as RawOption. It fails if no types are registered for
+// vendorClass or the payload is not in sub-option format.
+func (o VendorSpecificInformationOption) DecodeVendor(vendorClass string) (VendorSpecificInformationOption, error) {
+	registered, ok := lookupVendorSubOptions(vendorClass)
+	if !ok {
+		return o, fmt.Errorf("options: no vendor sub-options registered for %q", vendorClass)
+	}
+	subOptions, err := decodeSubOptions(OptionCodeVendorSpecificInformation, o.Encode(), registered)
+	if err != nil {
+		return o, err
+	}
+	o.SubOptions = subOptions
+	o.VendorClass = vendorClass
+	return o, nil
+}
+
+// decodeSubOptions parses data as encapsulated sub-options of option
+// code. Sub-options without a type in registered decode as RawOption.
+func decodeSubOptions(code OptionCode, data []byte, registered map[OptionCode]Option) (Options, error) {
+	var subOptions Options
+	for len(data) > 0 {
+		subCode := OptionCode(data[0])
+		if subCode == OptionCodeEnd {
+			break
+		}
+		if subCode == OptionCodePad {
+			data = data[1:]
+			continue
+		}
+		if len(data) < 2 || len(data) < 2+int(data[1]) {
+			return nil, &OptionError{Code: code, Err: errors.New("truncated vendor sub-option")}
+		}
+		value := data[2 : 2+int(data[1])]
+		subOption, ok := registered[subCode]
+		if !ok {
+			subOption = NewRawOption(subCode)
+		}
+		if unmarshaler, ok := subOption.(Unmarshaler); ok {
+			decoded, err := unmarshaler.Unmarshal(value)
+			if err != nil {
+				return nil, &OptionError{Code: code, Err: fmt.Errorf("sub-option %d: %w", subCode, err)}
+			}
+			subOptions = append(subOptions, decoded)
+		} else {
+			subOptions = append(subOptions, subOption.Decode(value))
+		}
+		data = data[2+int(data[1]):]
+	}
+	return subOptions, nil
+}
+
+// encodeSubOptions writes sub-options in code, length, data format.
+// Sub-options with more than 255 octets of data do not fit; they are
+// left out and reported in the error.
+func encodeSubOptions(buf *bytes.Buffer, subOptions Options) error {
+	var err error
+	for _, subOption := range subOptions {
+		data := subOption.Encode()
+		if len(data) > 255 {
+			if err == nil {
+				err = fmt.Errorf("sub-option %d too long: %d octets", subOption.Code(), len(data))
+			}
+			continue
+		}
+		buf.WriteByte(byte(subOption.Code()))
+		buf.WriteByte(byte(len(data)))
+		buf.Write(data)
+	}
+	return err
+}
+
+func (o VendorSpecificInformationOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	if len(o.SubOptions) == 0 {
+		buf.WriteString(fmt.Sprintf(" Vendor Specific Information: %s", hex.EncodeToString(o.Data)))
+		return buf.String()
+	}
+	buf.WriteString(fmt.Sprintf(" Vendor Specific Information (%s):", o.VendorClass))
+	for _, subOption := range o.SubOptions {
+		buf.WriteString(fmt.Sprintf(" [%s]", subOption))
+	}
+	return buf.String()
+}
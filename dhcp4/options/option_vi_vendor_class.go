@@ -0,0 +1,235 @@
+package options
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// VIVendorClass is the set of vendor classes sent for one enterprise.
+type VIVendorClass struct {
+	EnterpriseNumber uint32   `json:"enterprise_number"`
+	Classes          [][]byte `json:"classes"`
+}
+
+// Option124 Vendor-Identifying Vendor Class
+// https://www.rfc-editor.org/rfc/rfc3925#section-3
+// A DHCP client may use this option to unambiguously identify the
+// vendor that manufactured the hardware on which the client is running,
+// the software in use, or an industry consortium to which the vendor
+// belongs.  Each enterprise number, from the IANA "Private Enterprise
+// Numbers" registry, is followed by that vendor's classes.
+//
+//	                    1 1 1 1 1 1
+//	0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|  option-code  | option-len    |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|      enterprise-number1       |
+//	|                               |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|   data-len1   |               |
+//	+-+-+-+-+-+-+-+-+               |
+//	/      vendor-class-data1       /
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+ ----
+//	|      enterprise-number2       |   ^
+//	|                               |   |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+   |
+//	|   data-len2   |               | optional
+//	+-+-+-+-+-+-+-+-+               |   |
+//	/      vendor-class-data2       /   |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+   |
+//	~            ...                ~   V
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+ ----
+//
+// Each vendor-class-data holds one or more classes in the length
+// prefixed format of the user class option.
+type VIVendorClassOption struct {
+	Vendors []VIVendorClass `json:"vendors"`
+}
+
+// NewVIVendorClassOption returns an option carrying classes for a single
+// enterprise.
+func NewVIVendorClassOption(enterpriseNumber uint32, classes ...string) Option {
+	vendor := VIVendorClass{EnterpriseNumber: enterpriseNumber}
+	for _, class := range classes {
+		vendor.Classes = append(vendor.Classes, []byte(class))
+	}
+	return VIVendorClassOption{Vendors: []VIVendorClass{vendor}}
+}
+
+// Add appends classes for enterpriseNumber, grouping them with any the
+// option already holds for that enterprise.
+func (o VIVendorClassOption) Add(enterpriseNumber uint32, classes ...string) VIVendorClassOption {
+	vendors := append([]VIVendorClass(nil), o.Vendors...)
+	i := 0
+	for i < len(vendors) && vendors[i].EnterpriseNumber != enterpriseNumber {
+		i++
+	}
+	if i == len(vendors) {
+		vendors = append(vendors, VIVendorClass{EnterpriseNumber: enterpriseNumber})
+	}
+	existing := append([][]byte(nil), vendors[i].Classes...)
+	for _, class := range classes {
+		existing = append(existing, []byte(class))
+	}
+	vendors[i].Classes = existing
+	o.Vendors = vendors
+	return o
+}
+
+func (o VIVendorClassOption) Code() OptionCode {
+	return OptionCodeVIVendorClass
+}
+
+// Encode writes one record per enterprise, leaving out classes that
+// Validate reports.
+func (o VIVendorClassOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, enterpriseNumber := range o.enterpriseNumbers() {
+		writeEnterpriseRecord(&buf, enterpriseNumber, o.items(enterpriseNumber))
+	}
+	return buf.Bytes()
+}
+
+// Validate reports an empty class, or classes for one enterprise that
+// exceed the 255 octets of its record.
+func (o VIVendorClassOption) Validate() error {
+	for _, enterpriseNumber := range o.enterpriseNumbers() {
+		for _, class := range o.Classes(enterpriseNumber) {
+			if len(class) == 0 {
+				return fmt.Errorf("enterprise %d: empty class", enterpriseNumber)
+			}
+		}
+		if err := checkEnterpriseRecord(enterpriseNumber, o.items(enterpriseNumber)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enterpriseNumbers lists each enterprise of the option once, in order.
+func (o VIVendorClassOption) enterpriseNumbers() []uint32 {
+	var numbers []uint32
+	seen := make(map[uint32]bool)
+	for _, vendor := range o.Vendors {
+		if !seen[vendor.EnterpriseNumber] {
+			seen[vendor.EnterpriseNumber] = true
+			numbers = append(numbers, vendor.EnterpriseNumber)
+		}
+	}
+	return numbers
+}
+
+// items returns the length prefixed classes for enterpriseNumber.
+func (o VIVendorClassOption) items(enterpriseNumber uint32) [][]byte {
+	var items [][]byte
+	for _, class := range o.Classes(enterpriseNumber) {
+		if len(class) > 0 && len(class) <= 255 {
+			items = append(items, append([]byte{byte(len(class))}, class...))
+		}
+	}
+	return items
+}
+
+func (o VIVendorClassOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o VIVendorClassOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 5); err != nil {
+		return o, err
+	}
+	o.Vendors = nil
+	err := forEachEnterprise(b, func(enterpriseNumber uint32, data []byte) error {
+		vendor := VIVendorClass{EnterpriseNumber: enterpriseNumber}
+		for len(data) > 0 {
+			n := int(data[0])
+			if n == 0 || len(data) < 1+n {
+				return errors.New("malformed vendor class data")
+			}
+			vendor.Classes = append(vendor.Classes, data[1:1+n])
+			data = data[1+n:]
+		}
+		o.Vendors = append(o.Vendors, vendor)
+		return nil
+	})
+	return o, err
+}
+
+// Classes returns the classes sent for enterpriseNumber.
+func (o VIVendorClassOption) Classes(enterpriseNumber uint32) [][]byte {
+	var classes [][]byte
+	for _, vendor := range o.Vendors {
+		if vendor.EnterpriseNumber == enterpriseNumber {
+			classes = append(classes, vendor.Classes...)
+		}
+	}
+	return classes
+}
+
+// Has reports whether class was sent for enterpriseNumber.
+func (o VIVendorClassOption) Has(enterpriseNumber uint32, class string) bool {
+	for _, c := range o.Classes(enterpriseNumber) {
+		if string(c) == class {
+			return true
+		}
+	}
+	return false
+}
+
+func (o VIVendorClassOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" V-I Vendor Class:")
+	for _, vendor := range o.Vendors {
+		buf.WriteString(fmt.Sprintf(" [%d: %q]", vendor.EnterpriseNumber, vendor.Classes))
+	}
+	return buf.String()
+}
+
+// forEachEnterprise walks the enterprise-number, data-len, data records
+// shared by options 124 and 125.
+func forEachEnterprise(b []byte, fn func(enterpriseNumber uint32, data []byte) error) error {
+	for len(b) > 0 {
+		if len(b) < 5 || len(b) < 5+int(b[4]) {
+			return fmt.Errorf("%w: truncated enterprise record", ErrOptionLength)
+		}
+		n := int(b[4])
+		if err := fn(BytesToUint32(b), b[5:5+n]); err != nil {
+			return err
+		}
+		b = b[5+n:]
+	}
+	return nil
+}
+
+// writeEnterpriseRecord writes the one record RFC 3925 allows for
+// enterpriseNumber. Items that would push data-len past 255 are left
+// out.
+func writeEnterpriseRecord(buf *bytes.Buffer, enterpriseNumber uint32, items [][]byte) {
+	var data []byte
+	for _, item := range items {
+		if len(data)+len(item) <= 255 {
+			data = append(data, item...)
+		}
+	}
+	buf.Write(Uint32ToBytes(enterpriseNumber))
+	buf.WriteByte(byte(len(data)))
+	buf.Write(data)
+}
+
+// checkEnterpriseRecord reports items for enterpriseNumber that do not
+// fit in one record.
+func checkEnterpriseRecord(enterpriseNumber uint32, items [][]byte) error {
+	n := 0
+	for _, item := range items {
+		n += len(item)
+	}
+	if n > 255 {
+		return fmt.Errorf("enterprise %d: data too long: %d octets", enterpriseNumber, n)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package options
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+var (
+	viVendorMu         sync.RWMutex
+	viVendorSubOptions = map[uint32]map[OptionCode]Option{}
+)
+
+// RegisterVIVendorSubOption registers option as the type used to decode
+// the option 125 sub-option with its code for enterpriseNumber.
+// Sub-options without a registered type decode as RawOption.
+func RegisterVIVendorSubOption(enterpriseNumber uint32, option Option) {
+	viVendorMu.Lock()
+	defer viVendorMu.Unlock()
+	subOptions, ok := viVendorSubOptions[enterpriseNumber]
+	if !ok {
+		subOptions = make(map[OptionCode]Option)
+		viVendorSubOptions[enterpriseNumber] = subOptions
+	}
+	subOptions[option.Code()] = option
+}
+
+func lookupVIVendorSubOptions(enterpriseNumber uint32) map[OptionCode]Option {
+	viVendorMu.RLock()
+	defer viVendorMu.RUnlock()
+	return viVendorSubOptions[enterpriseNumber]
+}
+
+// VIVendorSpecific is the sub-options sent for one enterprise.
+type VIVendorSpecific struct {
+	EnterpriseNumber uint32  `json:"enterprise_number"`
+	SubOptions       Options `json:"sub_options"`
+}
+
+// Option125 Vendor-Identifying Vendor-Specific Information
+// https://www.rfc-editor.org/rfc/rfc3925#section-4
+// DHCP clients and servers may use this option to exchange
+// vendor-specific information.  Each enterprise number is followed by
+// that vendor's sub-options in code, length, data format.  An
+// enterprise number must not appear more than once, so decoding merges
+// repeated records.
+//
+//	                    1 1 1 1 1 1
+//	0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|  option-code  |  option-len   |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|      enterprise-number1       |
+//	|                               |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|   data-len1   |               |
+//	+-+-+-+-+-+-+-+-+ option-data1  |
+//	/                               /
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+ ----
+//	|      enterprise-number2       |   ^
+//	|                               |   |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+   |
+//	|   data-len2   |               | optional
+//	+-+-+-+-+-+-+-+-+ option-data2  |   |
+//	/                               /   |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+   |
+//	~            ...                ~   V
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+ ----
+type VIVendorSpecificInformationOption struct {
+	Vendors []VIVendorSpecific `json:"vendors"`
+}
+
+// NewVIVendorSpecificInformationOption returns an option carrying
+// sub-options for a single enterprise. Sub-options that do not fit in
+// the enterprise record are left out when the option is encoded; call
+// Validate on the result to find them.
+func NewVIVendorSpecificInformationOption(enterpriseNumber uint32, subOptions ...Option) Option {
+	return VIVendorSpecificInformationOption{
+		Vendors: []VIVendorSpecific{{EnterpriseNumber: enterpriseNumber, SubOptions: subOptions}},
+	}
+}
+
+// Add appends sub-options for enterpriseNumber, grouping them with any
+// the option already holds for that enterprise.
+func (o VIVendorSpecificInformationOption) Add(enterpriseNumber uint32, subOptions ...Option) VIVendorSpecificInformationOption {
+	vendors := append([]VIVendorSpecific(nil), o.Vendors...)
+	i := 0
+	for i < len(vendors) && vendors[i].EnterpriseNumber != enterpriseNumber {
+		i++
+	}
+	if i == len(vendors) {
+		vendors = append(vendors, VIVendorSpecific{EnterpriseNumber: enterpriseNumber})
+	}
+	vendors[i].SubOptions = append(append(Options(nil), vendors[i].SubOptions...), subOptions...)
+	o.Vendors = vendors
+	return o
+}
+
+func (o VIVendorSpecificInformationOption) Code() OptionCode {
+	return OptionCodeVIVendorSpecificInformation
+}
+
+// Encode writes one record per enterprise, leaving out sub-options
+// that Validate reports.
+func (o VIVendorSpecificInformationOption) Encode() []byte {
+	var buf bytes.Buffer
+	for _, enterpriseNumber := range o.enterpriseNumbers() {
+		writeEnterpriseRecord(&buf, enterpriseNumber, o.items(enterpriseNumber))
+	}
+	return buf.Bytes()
+}
+
+// Validate reports a sub-option too long to be encoded, or sub-options
+// for one enterprise that exceed the 255 octets of its record.
+func (o VIVendorSpecificInformationOption) Validate() error {
+	for _, enterpriseNumber := range o.enterpriseNumbers() {
+		for _, subOption := range o.allSubOptions(enterpriseNumber) {
+			var item bytes.Buffer
+			if err := encodeSubOptions(&item, Options{subOption}); err != nil {
+				return fmt.Errorf("enterprise %d: %w", enterpriseNumber, err)
+			}
+		}
+		if err := checkEnterpriseRecord(enterpriseNumber, o.items(enterpriseNumber)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enterpriseNumbers lists each enterprise of the option once, in order.
+func (o VIVendorSpecificInformationOption) enterpriseNumbers() []uint32 {
+	var numbers []uint32
+	seen := make(map[uint32]bool)
+	for _, vendor := range o.Vendors {
+		if !seen[vendor.EnterpriseNumber] {
+			seen[vendor.EnterpriseNumber] = true
+			numbers = append(numbers, vendor.EnterpriseNumber)
+		}
+	}
+	return numbers
+}
+
+// allSubOptions returns the sub-options of every entry for
+// enterpriseNumber.
+func (o VIVendorSpecificInformationOption) allSubOptions(enterpriseNumber uint32) Options {
+	var subOptions Options
+	for _, vendor := range o.Vendors {
+		if vendor.EnterpriseNumber == enterpriseNumber {
+			subOptions = append(subOptions, vendor.SubOptions...)
+		}
+	}
+	return subOptions
+}
+
+// items returns the encoded sub-options for enterpriseNumber.
+func (o VIVendorSpecificInformationOption) items(enterpriseNumber uint32) [][]byte {
+	var items [][]byte
+	for _, subOption := range o.allSubOptions(enterpriseNumber) {
+		var item bytes.Buffer
+		if encodeSubOptions(&item, Options{subOption}) == nil {
+			items = append(items, item.Bytes())
+		}
+	}
+	return items
+}
+
+func (o VIVendorSpecificInformationOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o VIVendorSpecificInformationOption) Unmarshal(b []byte) (Option, error) {
+	if err := checkMinLength(b, 5); err != nil {
+		return o, err
+	}
+	o.Vendors = nil
+	err := forEachEnterprise(b, func(enterpriseNumber uint32, data []byte) error {
+		subOptions, err := decodeSubOptions(OptionCodeVIVendorSpecificInformation, data, lookupVIVendorSubOptions(enterpriseNumber))
+		if err != nil {
+			return err
+		}
+		o = o.Add(enterpriseNumber, subOptions...)
+		return nil
+	})
+	return o, err
+}
+
+// SubOptions returns the sub-options sent for enterpriseNumber.
+func (o VIVendorSpecificInformationOption) SubOptions(enterpriseNumber uint32) Options {
+	for _, vendor := range o.Vendors {
+		if vendor.EnterpriseNumber == enterpriseNumber {
+			return vendor.SubOptions
+		}
+	}
+	return nil
+}
+
+func (o VIVendorSpecificInformationOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	buf.WriteString(" V-I Vendor Specific Information:")
+	for _, vendor := range o.Vendors {
+		buf.WriteString(fmt.Sprintf(" %d:", vendor.EnterpriseNumber))
+		for _, subOption := range vendor.SubOptions {
+			buf.WriteString(fmt.Sprintf(" [%s]", subOption))
+		}
+	}
+	return buf.String()
+}
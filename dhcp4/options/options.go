@@ -1,5 +1,10 @@
 package options
 
+import (
+	"errors"
+	"fmt"
+)
+
 // optionCode is a DHCP option code.
 type OptionCode uint8
 
@@ -10,7 +15,32 @@ type Option interface {
 	String() string
 }
 
+// Unmarshaler is implemented by options that validate their wire data.
+// Unmarshal returns an error instead of a partially decoded option when
+// the data is malformed.
+type Unmarshaler interface {
+	Unmarshal([]byte) (Option, error)
+}
+
+// ErrOptionLength is reported when option data has an invalid length.
+var ErrOptionLength = errors.New("invalid option length")
+
+// OptionError describes malformed data for a DHCP option.
+type OptionError struct {
+	Code OptionCode
+	Err  error
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("options: option %d: %s", e.Code, e.Err)
+}
+
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
 const (
+	OptionCodePad                             OptionCode = 0
 	OptionCodeSubnetMask                      OptionCode = 1
 	OptionCodeTimeOffset                      OptionCode = 2
 	OptionCodeRouter                          OptionCode = 3
@@ -67,42 +97,143 @@ const (
 	OptionCodeRebindingTime                   OptionCode = 59
 	OptionCodeVendorClassIdentifier           OptionCode = 60
 	OptionCodeClientIdentifier                OptionCode = 61
+	OptionCodeTFTPServerName                  OptionCode = 66
+	OptionCodeBootFileName                    OptionCode = 67
+	OptionCodeUserClass                       OptionCode = 77
+	OptionCodeRapidCommit                     OptionCode = 80
 	OptionCodeClientFullyQualifiedDomainName  OptionCode = 81
+	OptionCodeRelayAgentInformation           OptionCode = 82
+	OptionCodeAuthentication                  OptionCode = 90
+	OptionCodeClientLastTransactionTime       OptionCode = 91
+	OptionCodeAssociatedIP                    OptionCode = 92
+	OptionCodeClientSystemArchitecture        OptionCode = 93
+	OptionCodeClientNetworkInterface          OptionCode = 94
+	OptionCodeClientMachineIdentifier         OptionCode = 97
+	OptionCodeIPv6OnlyPreferred               OptionCode = 108
+	OptionCodeCaptivePortal                   OptionCode = 114
+	OptionCodeSubnetSelection                 OptionCode = 118
+	OptionCodeDomainSearch                    OptionCode = 119
+	OptionCodeClasslessStaticRoute            OptionCode = 121
+	OptionCodeVIVendorClass                   OptionCode = 124
+	OptionCodeVIVendorSpecificInformation     OptionCode = 125
+	OptionCodeForcerenewNonceCapable          OptionCode = 145
+	OptionCodeTFTPServerAddress               OptionCode = 150
+	OptionCodeStatusCode                      OptionCode = 151
+	OptionCodeEncryptedDNS                    OptionCode = 162
+	OptionCodeMicrosoftClasslessStaticRoute   OptionCode = 249
+	OptionCodeEnd                             OptionCode = 255
 )
 
+// optionTypes maps option codes to the types used to decode them. Use
+// RegisterOption to add to it.
 var optionTypes = map[OptionCode]Option{
-	OptionCodeSubnetMask:         SubnetMaskOption{},
-	OptionCodeRouter:             RouterOption{},
-	OptionCodeDomainNameServer:   DomainNameServerOption{},
-	OptionCodeNetworkTimeProtocolServers: NetworkTimeProtocolServersOption{},
-	OptionCodeHostName:           HostNameOption{},
-	OptionCodeDomainName:         DomainNameOption{},
-	OptionCodeBroadcastAddress:   BroadcastAddressOption{},
-	OptionCodeRequestedIPAddress: RequestedIPAddressOption{},
-	OptionCodeLeaseTime:          LeaseTimeOption{},
-	OptionCodeMessageType:        MessageTypeOption{},
-	OptionCodeServerIdentifier:   ServerIdentifierOption{},
-	OptionCodeParameterRequest:   ParameterRequestOption{},
-	OptionCodeMessage:            MessageOption{},
-	OptionCodeMaximumMessageSize: MaximumMessageSizeOption{},
-	OptionCodeRenewalTime:        RenewalTimeOption{},
-	OptionCodeRebindingTime:      RebindingTimeOption{},
-	OptionCodeClientIdentifier:   ClientIdentifierOption{},
-	108:                          Option108{},
-	138:                          Option138{},
+	OptionCodeSubnetMask:                      SubnetMaskOption{},
+	OptionCodeTimeOffset:                      TimeOffsetOption{},
+	OptionCodeRouter:                          RouterOption{},
+	OptionCodeTimeServer:                      TimeServerOption{},
+	OptionCodeNameServer:                      NameServerOption{},
+	OptionCodeDomainNameServer:                DomainNameServerOption{},
+	OptionCodeLogServer:                       LogServerOption{},
+	OptionCodeCookieServer:                    CookieServerOption{},
+	OptionCodeLPRServer:                       LPRServerOption{},
+	OptionCodeImpressServer:                   ImpressServerOption{},
+	OptionCodeResourceLocationServer:          ResourceLocationServerOption{},
+	OptionCodeHostName:                        HostNameOption{},
+	OptionCodeBootFileSize:                    BootFileSizeOption{},
+	OptionCodeDumpFile:                        DumpFileOption{},
+	OptionCodeDomainName:                      DomainNameOption{},
+	OptionCodeSwapServer:                      SwapServerOption{},
+	OptionCodeRootPath:                        RootPathOption{},
+	OptionCodeExtensionsPath:                  ExtensionsPathOption{},
+	OptionCodeIPForwarding:                    IPForwardingOption{},
+	OptionCodeNonLocalSourceRouting:           NonLocalSourceRoutingOption{},
+	OptionCodePolicyFilter:                    PolicyFilterOption{},
+	OptionCodeMaxDatagramReassembly:           MaxDatagramReassemblyOption{},
+	OptionCodeDefaultIPTimeToLive:             DefaultIPTimeToLiveOption{},
+	OptionCodePathMTUAgingTimeout:             PathMTUAgingTimeoutOption{},
+	OptionCodePathMTUPlateauTable:             PathMTUPlateauTableOption{},
+	OptionCodeInterfaceMTU:                    InterfaceMTUOption{},
+	OptionCodeAllSubnetsLocal:                 AllSubnetsLocalOption{},
+	OptionCodeBroadcastAddress:                BroadcastAddressOption{},
+	OptionCodePerformMaskDiscovery:            PerformMaskDiscoveryOption{},
+	OptionCodeMaskSupplier:                    MaskSupplierOption{},
+	OptionCodePerformRouterDiscovery:          PerformRouterDiscoveryOption{},
+	OptionCodeRouterSolicitationAddress:       RouterSolicitationAddressOption{},
+	OptionCodeStaticRoute:                     StaticRouteOption{},
+	OptionCodeTrailerEncapsulation:            TrailerEncapsulationOption{},
+	OptionCodeARPCacheTimeout:                 ARPCacheTimeoutOption{},
+	OptionCodeEthernetEncapsulation:           EthernetEncapsulationOption{},
+	OptionCodeTcpDefaultTTL:                   TCPDefaultTTLOption{},
+	OptionCodeTcpKeepaliveInterval:            TCPKeepaliveIntervalOption{},
+	OptionCodeTcpKeepaliveGarbage:             TCPKeepaliveGarbageOption{},
+	OptionCodeNetworkInformationServiceDomain: NetworkInformationServiceDomainOption{},
+	OptionCodeNetworkInformationServers:       NetworkInformationServersOption{},
+	OptionCodeNetworkTimeProtocolServers:      NetworkTimeProtocolServersOption{},
+	OptionCodeVendorSpecificInformation:       VendorSpecificInformationOption{},
+	OptionCodeNetBIOSNameServer:               NetBIOSNameServerOption{},
+	OptionCodeRequestedIPAddress:              RequestedIPAddressOption{},
+	OptionCodeLeaseTime:                       LeaseTimeOption{},
+	OptionCodeOverload:                        OverloadOption{},
+	OptionCodeMessageType:                     MessageTypeOption{},
+	OptionCodeServerIdentifier:                ServerIdentifierOption{},
+	OptionCodeParameterRequest:                ParameterRequestOption{},
+	OptionCodeMessage:                         MessageOption{},
+	OptionCodeMaximumMessageSize:              MaximumMessageSizeOption{},
+	OptionCodeRenewalTime:                     RenewalTimeOption{},
+	OptionCodeRebindingTime:                   RebindingTimeOption{},
+	OptionCodeVendorClassIdentifier:           VendorClassIdentifierOption{},
+	OptionCodeClientIdentifier:                ClientIdentifierOption{},
+	OptionCodeRapidCommit:                     RapidCommitOption{},
+	OptionCodeClientFullyQualifiedDomainName:  ClientFQDNOption{},
+	OptionCodeRelayAgentInformation:           RelayAgentInformationOption{},
+	OptionCodeDomainSearch:                    DomainSearchOption{},
+	OptionCodeClasslessStaticRoute:            ClasslessStaticRouteOption{code: OptionCodeClasslessStaticRoute},
+	OptionCodeMicrosoftClasslessStaticRoute:   ClasslessStaticRouteOption{code: OptionCodeMicrosoftClasslessStaticRoute},
+	OptionCodeUserClass:                       UserClassOption{},
+	OptionCodeVIVendorClass:                   VIVendorClassOption{},
+	OptionCodeVIVendorSpecificInformation:     VIVendorSpecificInformationOption{},
+	OptionCodeTFTPServerName:                  TFTPServerNameOption{},
+	OptionCodeBootFileName:                    BootFileNameOption{},
+	OptionCodeAuthentication:                  AuthenticationOption{},
+	OptionCodeClientLastTransactionTime:       ClientLastTransactionTimeOption{},
+	OptionCodeAssociatedIP:                    AssociatedIPOption{},
+	OptionCodeClientSystemArchitecture:        ClientSystemArchitectureOption{},
+	OptionCodeClientNetworkInterface:          ClientNetworkInterfaceOption{},
+	OptionCodeClientMachineIdentifier:         ClientMachineIdentifierOption{},
+	OptionCodeForcerenewNonceCapable:          ForcerenewNonceCapableOption{},
+	OptionCodeTFTPServerAddress:               TFTPServerAddressOption{},
+	OptionCodeStatusCode:                      StatusCodeOption{},
+	OptionCodeEncryptedDNS:                    EncryptedDNSOption{},
+	OptionCodeIPv6OnlyPreferred:               IPv6OnlyPreferredOption{},
+	OptionCodeCaptivePortal:                   CaptivePortalOption{},
+	OptionCodeSubnetSelection:                 SubnetSelectionOption{},
+	138:                                       Option138{},
 	// option95: LDAP
-	// option108: IPv6-Only Preferred
-	// option114: DHCP Captive-Portal(URL)
-	// option118: Subnet Selection Option
-	// option119: DNS Domain Search List
-	// option121: Classless Static Route
 	// option252: Private/Proxy autodiscovery
 }
 
-func ParseOption(code OptionCode, data []byte) Option {
-	option, ok := optionTypes[code]
+// DecodeOption decodes data as the option registered for code. Malformed
+// data is reported as an *OptionError together with a RawOption holding
+// the original bytes.
+func DecodeOption(code OptionCode, data []byte) (Option, error) {
+	option, ok := lookupOption(code)
 	if !ok {
 		option = NewRawOption(code)
 	}
-	return option.Decode(data)
+	unmarshaler, ok := option.(Unmarshaler)
+	if !ok {
+		return option.Decode(data), nil
+	}
+	decoded, err := unmarshaler.Unmarshal(data)
+	if err != nil {
+		return NewRawOption(code).Decode(data), &OptionError{Code: code, Err: err}
+	}
+	return decoded, nil
+}
+
+// ParseOption decodes data as the option registered for code. Malformed
+// data decodes as a RawOption.
+func ParseOption(code OptionCode, data []byte) Option {
+	option, _ := DecodeOption(code, data)
+	return option
 }
@@ -0,0 +1,480 @@
+package options
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestDecodeOptionRejectsShortData(t *testing.T) {
+	for code := range optionTypes {
+		option, err := DecodeOption(code, nil)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, ErrOptionLength) {
+			t.Errorf("option %d: err = %v, want ErrOptionLength", code, err)
+		}
+		if _, ok := option.(RawOption); !ok {
+			t.Errorf("option %d: decoded %T, want RawOption", code, option)
+		}
+	}
+}
+
+func FuzzDecodeOption(f *testing.F) {
+	f.Add(uint8(OptionCodeRouter), []byte{192, 0, 2, 1})
+	f.Add(uint8(OptionCodeClientIdentifier), []byte{1, 0, 0x11, 0x22, 0x33, 0x44, 0x55})
+	f.Add(uint8(OptionCodeMessageType), []byte{1})
+	f.Fuzz(func(t *testing.T, code uint8, data []byte) {
+		option, err := DecodeOption(OptionCode(code), data)
+		if err != nil {
+			return
+		}
+		_ = option.String()
+		encoded := option.Encode()
+		again, err := DecodeOption(OptionCode(code), encoded)
+		if err != nil {
+			t.Fatalf("re-decode %x: %v", encoded, err)
+		}
+		if !bytes.Equal(again.Encode(), encoded) {
+			t.Fatalf("option %d changed across round trip", code)
+		}
+	})
+}
+
+type siteOption struct {
+	RawOption
+}
+
+func (o siteOption) Code() OptionCode { return 250 }
+
+func (o siteOption) Decode(b []byte) Option {
+	o.Data = b
+	return o
+}
+
+func TestRegisterOption(t *testing.T) {
+	if err := RegisterOption(siteOption{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { unregisterOption(250) })
+	if _, ok := ParseOption(250, []byte{1}).(siteOption); !ok {
+		t.Fatalf("option 250 decoded as %T", ParseOption(250, []byte{1}))
+	}
+	if err := RegisterOption(NewRawOption(OptionCodeEnd)); err == nil {
+		t.Fatal("registered End option")
+	}
+}
+
+func TestRegisterOptionType(t *testing.T) {
+	if err := RegisterOptionType(251, "proxy", OptionTypeIPList); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { unregisterOption(251) })
+	found := false
+	for _, code := range RegisteredCodes() {
+		found = found || code == 251
+	}
+	if !found {
+		t.Fatal("option 251 not listed in RegisteredCodes")
+	}
+	encoded := mustTypedOption(t, 251, OptionTypeIPList, []net.IP{net.IPv4(192, 0, 2, 1)}).Encode()
+	option, err := DecodeOption(251, encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typed := option.(TypedOption)
+	if typed.Name != "proxy" || typed.Value.([]net.IP)[0].String() != "192.0.2.1" {
+		t.Fatalf("decoded %v", typed)
+	}
+	if _, err := DecodeOption(251, []byte{1, 2}); !errors.Is(err, ErrOptionLength) {
+		t.Fatalf("err = %v, want ErrOptionLength", err)
+	}
+	if _, err := NewTypedOption(251, OptionTypeIPList, "192.0.2.1"); err == nil {
+		t.Fatal("accepted string value for ip-list option")
+	}
+	if _, err := NewTypedOption(251, OptionType(42), nil); err == nil {
+		t.Fatal("accepted unknown option type")
+	}
+}
+
+func mustTypedOption(t *testing.T, code OptionCode, typ OptionType, value interface{}) Option {
+	t.Helper()
+	option, err := NewTypedOption(code, typ, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return option
+}
+
+func TestSubOptionLengthBoundary(t *testing.T) {
+	fits := mustTypedOption(t, 1, OptionTypeBytes, make([]byte, 255))
+	tooLong := mustTypedOption(t, 2, OptionTypeBytes, make([]byte, 256))
+	option := NewVendorSpecificInformationOption(fits).(VendorSpecificInformationOption)
+	if err := option.Validate(); err != nil || len(option.Encode()) != 257 {
+		t.Fatalf("255 octets: err = %v, length %d", err, len(option.Encode()))
+	}
+	option = NewVendorSpecificInformationOption(fits, tooLong).(VendorSpecificInformationOption)
+	if err := option.Validate(); err == nil || !strings.Contains(err.Error(), "sub-option 2 too long") || len(option.Encode()) != 257 {
+		t.Fatalf("256 octets: err = %v, length %d", err, len(option.Encode()))
+	}
+
+	fits = mustTypedOption(t, 1, OptionTypeBytes, make([]byte, 253))
+	tooLong = mustTypedOption(t, 2, OptionTypeBytes, make([]byte, 254))
+	vi := NewVIVendorSpecificInformationOption(3561, fits).(VIVendorSpecificInformationOption)
+	if err := vi.Validate(); err != nil || len(vi.Encode()) != 5+255 {
+		t.Fatalf("253 octets: err = %v, length %d", err, len(vi.Encode()))
+	}
+	vi = NewVIVendorSpecificInformationOption(3561, fits, tooLong).(VIVendorSpecificInformationOption)
+	if err := vi.Validate(); err == nil || len(vi.Encode()) != 5+255 {
+		t.Fatalf("254 octets: err = %v, length %d", err, len(vi.Encode()))
+	}
+}
+
+func TestRFC2132OptionsRoundTrip(t *testing.T) {
+	tests := []Option{
+		NewTimeOffsetOption(-18000),
+		NewTimeServerOption([]string{"192.0.2.1", "192.0.2.2"}),
+		NewInterfaceMTUOption(1400),
+		NewIPForwardingOption(true),
+		NewDefaultIPTimeToLiveOption(64),
+		NewStaticRouteOption([]StaticRoute{NewStaticRoute("198.51.100.0", "192.0.2.1")}),
+		NewPolicyFilterOption([]string{"10.0.0.0/8"}),
+		NewPathMTUPlateauTableOption([]uint16{576, 1500}),
+		NewRootPathOption("/srv/root"),
+		NewBootFileSizeOption(2048),
+	}
+	for _, option := range tests {
+		decoded, err := DecodeOption(option.Code(), option.Encode())
+		if err != nil {
+			t.Errorf("option %d: %v", option.Code(), err)
+			continue
+		}
+		if decoded.String() != option.String() {
+			t.Errorf("option %d: decoded %s, want %s", option.Code(), decoded, option)
+		}
+	}
+	if _, err := DecodeOption(OptionCodeInterfaceMTU, []byte{0, 10}); err == nil {
+		t.Error("accepted interface MTU below 68")
+	}
+	if _, err := DecodeOption(OptionCodeIPForwarding, []byte{2}); err == nil {
+		t.Error("accepted boolean value 2")
+	}
+}
+
+func TestClasslessStaticRouteEncoding(t *testing.T) {
+	_, branch, _ := net.ParseCIDR("10.17.0.0/16")
+	_, defaultRoute, _ := net.ParseCIDR("0.0.0.0/0")
+	option := NewClasslessStaticRouteOption([]ClasslessRoute{
+		NewClasslessRoute(*branch, net.IPv4(192, 0, 2, 1)),
+		NewClasslessRoute(*defaultRoute, net.IPv4(192, 0, 2, 254)),
+	})
+	want := []byte{16, 10, 17, 192, 0, 2, 1, 0, 192, 0, 2, 254}
+	if !bytes.Equal(option.Encode(), want) {
+		t.Fatalf("encoded %v, want %v", option.Encode(), want)
+	}
+	decoded, err := DecodeOption(OptionCodeMicrosoftClasslessStaticRoute, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := decoded.(ClasslessStaticRouteOption).Routes
+	if decoded.Code() != 249 || len(routes) != 2 || routes[0].Destination.String() != "10.17.0.0/16" {
+		t.Fatalf("decoded %s", decoded)
+	}
+	if _, err := DecodeOption(OptionCodeClasslessStaticRoute, []byte{33, 1, 2, 3, 4, 5, 6, 7, 8}); err == nil {
+		t.Fatal("accepted mask width 33")
+	}
+}
+
+func TestDomainSearchCompression(t *testing.T) {
+	option := NewDomainSearchOption([]string{"eng.example.com", "example.com", "corp.example.com."})
+	want := []byte{
+		3, 'e', 'n', 'g', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		0xc0, 4,
+		4, 'c', 'o', 'r', 'p', 0xc0, 4,
+	}
+	if !bytes.Equal(option.Encode(), want) {
+		t.Fatalf("encoded %v, want %v", option.Encode(), want)
+	}
+	decoded, err := DecodeOption(OptionCodeDomainSearch, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	domains := decoded.(DomainSearchOption).Domains
+	if strings.Join(domains, ",") != "eng.example.com,example.com,corp.example.com" {
+		t.Fatalf("decoded %v", domains)
+	}
+	loops := [][]byte{
+		{0xc0, 0},
+		{3, 'c', 'o', 'm', 0xc0, 0},
+		{1, 'a', 0, 1, 'b', 0xc0, 3},
+	}
+	for _, data := range loops {
+		if _, err := DecodeOption(OptionCodeDomainSearch, data); err == nil {
+			t.Errorf("accepted pointer loop %v", data)
+		}
+	}
+
+	long := strings.Repeat("a", 64)
+	tooLong := strings.TrimSuffix(strings.Repeat(strings.Repeat("b", 63)+".", 4), ".")
+	option = NewDomainSearchOption([]string{"bad..example.com", long + ".example.com", tooLong, "example.com"})
+	want = []byte{7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0}
+	if !bytes.Equal(option.Encode(), want) {
+		t.Fatalf("encoded %v, want only example.com", option.Encode())
+	}
+	if _, err := DecodeOption(OptionCodeDomainSearch, []byte{3, 'a', '.', 'b', 0}); err == nil {
+		t.Error("accepted label containing a dot")
+	}
+}
+
+func TestClientFQDN(t *testing.T) {
+	client := NewClientFQDNOption("host.example.com.", false)
+	want := []byte{FQDNFlagE, 0, 0, 4, 'h', 'o', 's', 't', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0}
+	if !bytes.Equal(client.Encode(), want) {
+		t.Fatalf("encoded %v, want %v", client.Encode(), want)
+	}
+	decoded, err := DecodeOption(OptionCodeClientFullyQualifiedDomainName, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fqdn := decoded.(ClientFQDNOption)
+	if fqdn.DomainName != "host.example.com." {
+		t.Fatalf("name = %q", fqdn.DomainName)
+	}
+	reply := fqdn.Reply(fqdn.DomainName, true)
+	if reply.Flags != FQDNFlagE|FQDNFlagS|FQDNFlagO || reply.RCode1 != 255 || reply.RCode2 != 255 {
+		t.Fatalf("reply = %+v", reply)
+	}
+	noUpdate := ClientFQDNOption{Flags: FQDNFlagN, DomainName: "host"}
+	if reply := noUpdate.Reply("host", false); reply.Flags != FQDNFlagN {
+		t.Fatalf("reply flags = %#x, want N", reply.Flags)
+	}
+	if reply := fqdn.NoUpdateReply(fqdn.DomainName); reply.Flags != FQDNFlagE|FQDNFlagN {
+		t.Fatalf("no-update reply flags = %#x, want E|N", reply.Flags)
+	}
+	serverUpdate := NewClientFQDNOption("host", true).(ClientFQDNOption)
+	if reply := serverUpdate.NoUpdateReply("host"); reply.Flags != FQDNFlagE|FQDNFlagN|FQDNFlagO {
+		t.Fatalf("no-update reply flags = %#x, want E|N|O", reply.Flags)
+	}
+	for _, name := range []string{"host..example.com", strings.Repeat("a", 64) + ".com"} {
+		invalid := NewClientFQDNOption(name, false).(ClientFQDNOption)
+		if invalid.Validate() == nil || invalid.Encode() != nil {
+			t.Errorf("%q: encoded %v", name, invalid.Encode())
+		}
+	}
+	if _, err := DecodeOption(OptionCodeClientFullyQualifiedDomainName, []byte{FQDNFlagN | FQDNFlagS, 0, 0}); err == nil {
+		t.Fatal("accepted both N and S flags")
+	}
+}
+
+func TestNetworkBootOptionsRoundTrip(t *testing.T) {
+	uuid := []byte{0x4c, 0x4c, 0x45, 0x44, 0x00, 0x51, 0x38, 0x10, 0x80, 0x33, 0xb4, 0xc0, 0x4f, 0x56, 0x31, 0x32}
+	tests := []Option{
+		NewTFTPServerNameOption("192.0.2.5"),
+		NewBootFileNameOption("pxelinux.0"),
+		NewClientSystemArchitectureOption(ArchitectureX64UEFI, ArchitectureX86BIOS),
+		NewClientNetworkInterfaceOption(3, 16),
+		NewClientMachineIdentifierOption(uuid),
+		NewTFTPServerAddressOption([]string{"192.0.2.5", "192.0.2.6"}),
+	}
+	for _, option := range tests {
+		decoded, err := DecodeOption(option.Code(), option.Encode())
+		if err != nil {
+			t.Errorf("option %d: %v", option.Code(), err)
+			continue
+		}
+		if decoded.String() != option.String() {
+			t.Errorf("option %d: decoded %s, want %s", option.Code(), decoded, option)
+		}
+	}
+	if _, err := DecodeOption(OptionCodeClientSystemArchitecture, []byte{0, 7, 0}); err == nil {
+		t.Error("accepted odd-length architecture list")
+	}
+	if _, err := DecodeOption(OptionCodeClientMachineIdentifier, uuid); err == nil {
+		t.Error("accepted machine identifier without type")
+	}
+	machine := NewClientMachineIdentifierOption(uuid).(ClientMachineIdentifierOption)
+	if got := machine.UUIDString(); got != "4c4c4544-0051-3810-8033-b4c04f563132" {
+		t.Errorf("UUID = %s", got)
+	}
+}
+
+func TestUserClass(t *testing.T) {
+	option := NewUserClassOption("kiosk", "lab")
+	decoded, err := DecodeOption(OptionCodeUserClass, option.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	userClass := decoded.(UserClassOption)
+	if len(userClass.Classes) != 2 || !userClass.Has("lab") || userClass.Raw {
+		t.Fatalf("decoded %s", userClass)
+	}
+	// Windows sends the class without a length prefix.
+	decoded, err = DecodeOption(OptionCodeUserClass, []byte("MSFT 5.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	userClass = decoded.(UserClassOption)
+	if !userClass.Has("MSFT 5.0") || string(userClass.Encode()) != "MSFT 5.0" {
+		t.Fatalf("decoded %s", userClass)
+	}
+}
+
+func TestVIVendorOptions(t *testing.T) {
+	class := NewVIVendorClassOption(4491, "docsis3.0").(VIVendorClassOption).Add(9, "ios").Add(4491, "eRouter1.0")
+	decoded, err := DecodeOption(OptionCodeVIVendorClass, class.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendorClass := decoded.(VIVendorClassOption)
+	if len(vendorClass.Vendors) != 2 || !vendorClass.Has(4491, "eRouter1.0") || vendorClass.Has(9, "docsis3.0") {
+		t.Fatalf("decoded %s", vendorClass)
+	}
+
+	RegisterVIVendorSubOption(3561, mustTypedOption(t, 1, OptionTypeString, nil))
+	var subOptions []Option
+	for i := 0; i < 30; i++ {
+		subOptions = append(subOptions, mustTypedOption(t, 1, OptionTypeString, "0123456789"))
+	}
+	specific := NewVIVendorSpecificInformationOption(3561, subOptions...).(VIVendorSpecificInformationOption)
+	data := specific.Encode()
+	// Only 21 sub-options of 12 bytes fit in the one enterprise record.
+	if data[4] != 252 || len(data) != 5+252 || specific.Validate() == nil {
+		t.Fatalf("data-len %d, total %d, err = %v", data[4], len(data), specific.Validate())
+	}
+	decoded, err = DecodeOption(OptionCodeVIVendorSpecificInformation, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendorSpecific := decoded.(VIVendorSpecificInformationOption)
+	got := vendorSpecific.SubOptions(3561)
+	if len(vendorSpecific.Vendors) != 1 || len(got) != 21 || got[20].(TypedOption).Value != "0123456789" {
+		t.Fatalf("decoded %s", vendorSpecific)
+	}
+	split := VIVendorSpecificInformationOption{Vendors: []VIVendorSpecific{
+		{EnterpriseNumber: 3561, SubOptions: subOptions[:1]},
+		{EnterpriseNumber: 9, SubOptions: subOptions[:1]},
+		{EnterpriseNumber: 3561, SubOptions: subOptions[:1]},
+	}}
+	if data := split.Encode(); len(data) != 5+24+5+12 || data[4] != 24 || split.Validate() != nil {
+		t.Fatalf("encoded %v", data)
+	}
+	tooMany := NewVIVendorClassOption(4491, strings.Repeat("a", 200), strings.Repeat("b", 100)).(VIVendorClassOption)
+	if data := tooMany.Encode(); len(data) != 5+201 || tooMany.Validate() == nil {
+		t.Fatalf("classes: length %d, err = %v", len(data), tooMany.Validate())
+	}
+	if _, err := DecodeOption(OptionCodeVIVendorSpecificInformation, []byte{0, 0, 13, 185, 4, 1, 5}); err == nil {
+		t.Error("accepted truncated sub-option")
+	}
+}
+
+func TestAuthenticationOption(t *testing.T) {
+	option := NewDelayedAuthenticationOption(1<<40|5, 0xdeadbeef)
+	decoded, err := DecodeOption(OptionCodeAuthentication, option.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := decoded.(AuthenticationOption)
+	secretID, ok := auth.SecretID()
+	if !ok || secretID != 0xdeadbeef || auth.ReplayDetection != 1<<40|5 || len(auth.MAC()) != 16 {
+		t.Fatalf("decoded %s", auth)
+	}
+	request, err := DecodeOption(OptionCodeAuthentication, NewDelayedAuthenticationRequestOption(1).Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := request.(AuthenticationOption).SecretID(); ok {
+		t.Fatal("request option has a secret ID")
+	}
+	if _, err := DecodeOption(OptionCodeAuthentication, []byte{1, 1, 0}); err == nil {
+		t.Error("accepted option without replay detection")
+	}
+}
+
+func TestEncryptedDNSOption(t *testing.T) {
+	option := NewEncryptedDNSOption(
+		NewDNRInstance(1, "dot.example.net").WithAddresses("192.0.2.53", "192.0.2.54").WithALPN("dot").WithPort(8853),
+		NewDNRInstance(2, "doh.example.net.").WithAddresses("198.51.100.53").WithALPN("h2", "h3").WithDoHPath("/dns-query{?dns}"),
+		NewDNRInstance(3, "resolver.example.net"),
+	).(EncryptedDNSOption)
+	if err := option.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeOption(OptionCodeEncryptedDNS, option.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolvers := decoded.(EncryptedDNSOption).Resolvers
+	if len(resolvers) != 3 {
+		t.Fatalf("decoded %s", decoded)
+	}
+	dot, doh, adnOnly := resolvers[0], resolvers[1], resolvers[2]
+	if dot.ADN != "dot.example.net" || len(dot.Addresses) != 2 || dot.Params.Port != 8853 || dot.Params.ALPN[0] != "dot" {
+		t.Errorf("dot resolver %+v", dot)
+	}
+	if doh.ADN != "doh.example.net" || doh.Params.DoHPath != "/dns-query{?dns}" || len(doh.Params.ALPN) != 2 {
+		t.Errorf("doh resolver %+v", doh)
+	}
+	if !adnOnly.ADNOnly() || adnOnly.Priority != 3 {
+		t.Errorf("adn-only resolver %+v", adnOnly)
+	}
+
+	invalid := []DNRInstance{
+		NewDNRInstance(1, "dot.example.net").WithAddresses("192.0.2.53"),
+		NewDNRInstance(1, "doh.example.net").WithAddresses("192.0.2.53").WithALPN("h2"),
+		NewDNRInstance(1, "doh.example.net").WithAddresses("192.0.2.53").WithALPN("h2").WithDoHPath("/dns-query"),
+		NewDNRInstance(1, "dot.example.net").WithAddresses("0.0.0.0").WithALPN("dot"),
+		NewDNRInstance(1, "dot.example.net").WithALPN("dot"),
+		NewDNRInstance(1, "bad..example.net"),
+		NewDNRInstance(1, ""),
+	}
+	for _, r := range invalid {
+		if err := NewEncryptedDNSOption(r).(EncryptedDNSOption).Validate(); err == nil {
+			t.Errorf("accepted %+v", r)
+		}
+		data := append(Uint16ToBytes(uint16(len(r.encode()))), r.encode()...)
+		if _, err := DecodeOption(OptionCodeEncryptedDNS, data); err == nil {
+			t.Errorf("decoded %+v", r)
+		}
+	}
+
+	mixed := NewEncryptedDNSOption(
+		NewDNRInstance(1, "a..b"),
+		NewDNRInstance(2, "dot.example.net").WithAddresses("bogus").WithALPN("dot"),
+		NewDNRInstance(3, "dot.example.net").WithAddresses("2001:db8::53").WithALPN("dot"),
+		NewDNRInstance(4, "resolver.example.net"),
+	)
+	decoded, err = DecodeOption(OptionCodeEncryptedDNS, mixed.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolvers := decoded.(EncryptedDNSOption).Resolvers; len(resolvers) != 1 || resolvers[0].Priority != 4 {
+		t.Errorf("encoded invalid resolvers: %s", decoded)
+	}
+
+	valid := NewDNRInstance(1, "dot.example.net").WithAddresses("192.0.2.53").WithALPN("dot").WithPort(853).encode()
+	outOfOrder := append([]byte(nil), valid[:len(valid)-14]...)
+	outOfOrder = append(outOfOrder, 0, 3, 0, 2, 3, 85, 0, 1, 0, 4, 3, 'd', 'o', 't')
+	compressed := []byte{0, 1, 2, 0xc0, 0}
+	for _, instance := range [][]byte{outOfOrder, compressed, valid[:len(valid)-1]} {
+		data := append(Uint16ToBytes(uint16(len(instance))), instance...)
+		if _, err := DecodeOption(OptionCodeEncryptedDNS, data); err == nil {
+			t.Errorf("decoded malformed instance %x", instance)
+		}
+	}
+}
+
+func TestCaptivePortalOption(t *testing.T) {
+	decoded, err := DecodeOption(OptionCodeCaptivePortal, []byte(CaptivePortalUnrestricted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.(CaptivePortalOption).Unrestricted() {
+		t.Fatalf("decoded %s", decoded)
+	}
+	if _, err := DecodeOption(OptionCodeCaptivePortal, []byte("/portal")); err == nil {
+		t.Error("accepted relative URI")
+	}
+}
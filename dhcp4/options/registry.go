@@ -0,0 +1,255 @@
+package options
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+)
+
+var registryMu sync.RWMutex
+
+// RegisterOption registers option as the type used to decode its code,
+// replacing any earlier registration. It is meant for vendor-private and
+// site-specific options (codes 224-254) but may override any code except
+// Pad and End.
+func RegisterOption(option Option) error {
+	code := option.Code()
+	if code == OptionCodePad || code == OptionCodeEnd {
+		return fmt.Errorf("options: cannot register option %d", code)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	optionTypes[code] = option
+	return nil
+}
+
+// RegisterOptionType registers code as an option of the declared type, so
+// it decodes as a TypedOption without a dedicated Go type.
+func RegisterOptionType(code OptionCode, name string, t OptionType) error {
+	if t > OptionTypeBool {
+		return fmt.Errorf("options: unknown option type %d", t)
+	}
+	return RegisterOption(TypedOption{code: code, Name: name, Type: t})
+}
+
+// unregisterOption removes the registration of code.
+func unregisterOption(code OptionCode) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(optionTypes, code)
+}
+
+// RegisteredCodes returns the registered option codes in ascending order.
+func RegisteredCodes() []OptionCode {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	codes := make([]OptionCode, 0, len(optionTypes))
+	for code := range optionTypes {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+func lookupOption(code OptionCode) (Option, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	option, ok := optionTypes[code]
+	return option, ok
+}
+
+// OptionType is the declared wire format of a TypedOption.
+type OptionType uint8
+
+const (
+	OptionTypeBytes  OptionType = iota // opaque bytes, Value is []byte
+	OptionTypeIP                       // one IPv4 address, Value is net.IP
+	OptionTypeIPList                   // IPv4 addresses, Value is []net.IP
+	OptionTypeString                   // text, Value is string
+	OptionTypeUint8                    // Value is uint8
+	OptionTypeUint16                   // Value is uint16
+	OptionTypeUint32                   // Value is uint32
+	OptionTypeBool                     // one octet 0 or 1, Value is bool
+)
+
+func (t OptionType) String() string {
+	switch t {
+	case OptionTypeBytes:
+		return "bytes"
+	case OptionTypeIP:
+		return "ip"
+	case OptionTypeIPList:
+		return "ip-list"
+	case OptionTypeString:
+		return "string"
+	case OptionTypeUint8:
+		return "uint8"
+	case OptionTypeUint16:
+		return "uint16"
+	case OptionTypeUint32:
+		return "uint32"
+	case OptionTypeBool:
+		return "bool"
+	default:
+		return "unknown"
+	}
+}
+
+// TypedOption is an option registered with RegisterOptionType. Its Value
+// holds the Go type documented for its OptionType.
+type TypedOption struct {
+	code  OptionCode
+	Name  string
+	Type  OptionType
+	Value interface{}
+}
+
+// NewTypedOption returns a typed option for code. The name is taken from
+// the registry when code was registered with RegisterOptionType. value
+// must have the Go type documented for t, or be nil for an option only
+// used to register a sub-option type.
+func NewTypedOption(code OptionCode, t OptionType, value interface{}) (Option, error) {
+	if err := checkTypedValue(t, value); err != nil {
+		return nil, err
+	}
+	o := TypedOption{code: code, Type: t, Value: value}
+	if registered, ok := lookupOption(code); ok {
+		if typed, ok := registered.(TypedOption); ok {
+			o.Name = typed.Name
+		}
+	}
+	return o, nil
+}
+
+// checkTypedValue reports an error when value is not nil and does not
+// have the Go type of t.
+func checkTypedValue(t OptionType, value interface{}) error {
+	if t > OptionTypeBool {
+		return fmt.Errorf("options: unknown option type %d", t)
+	}
+	if value == nil {
+		return nil
+	}
+	ok := false
+	switch value.(type) {
+	case []byte:
+		ok = t == OptionTypeBytes
+	case net.IP:
+		ok = t == OptionTypeIP
+	case []net.IP:
+		ok = t == OptionTypeIPList
+	case string:
+		ok = t == OptionTypeString
+	case uint8:
+		ok = t == OptionTypeUint8
+	case uint16:
+		ok = t == OptionTypeUint16
+	case uint32:
+		ok = t == OptionTypeUint32
+	case bool:
+		ok = t == OptionTypeBool
+	}
+	if !ok {
+		return fmt.Errorf("options: %T is not a %s value", value, t)
+	}
+	return nil
+}
+
+func (o TypedOption) Code() OptionCode {
+	return o.code
+}
+
+func (o TypedOption) Encode() []byte {
+	switch v := o.Value.(type) {
+	case []byte:
+		return v
+	case net.IP:
+		return v.To4()
+	case []net.IP:
+		var buf bytes.Buffer
+		for _, ip := range v {
+			buf.Write(ip.To4())
+		}
+		return buf.Bytes()
+	case string:
+		return []byte(v)
+	case uint8:
+		return Uint8ToBytes(v)
+	case uint16:
+		return Uint16ToBytes(v)
+	case uint32:
+		return Uint32ToBytes(v)
+	case bool:
+		return boolToBytes(v)
+	default:
+		return nil
+	}
+}
+
+func (o TypedOption) Decode(b []byte) Option {
+	option, _ := o.Unmarshal(b)
+	return option
+}
+
+func (o TypedOption) Unmarshal(b []byte) (Option, error) {
+	var err error
+	switch o.Type {
+	case OptionTypeIP:
+		err = checkLength(b, 4)
+	case OptionTypeIPList:
+		err = checkMultiple(b, 4)
+	case OptionTypeString:
+		err = checkMinLength(b, 1)
+	case OptionTypeUint8, OptionTypeBool:
+		err = checkLength(b, 1)
+	case OptionTypeUint16:
+		err = checkLength(b, 2)
+	case OptionTypeUint32:
+		err = checkLength(b, 4)
+	}
+	if err != nil {
+		return o, err
+	}
+	switch o.Type {
+	case OptionTypeIP:
+		o.Value = net.IP(b)
+	case OptionTypeIPList:
+		o.Value = decodeIPs(b)
+	case OptionTypeString:
+		o.Value = string(b)
+	case OptionTypeUint8:
+		o.Value = b[0]
+	case OptionTypeUint16:
+		o.Value = BytesToUint16(b)
+	case OptionTypeUint32:
+		o.Value = BytesToUint32(b)
+	case OptionTypeBool:
+		value, err := bytesToBool(b)
+		if err != nil {
+			return o, err
+		}
+		o.Value = value
+	default:
+		o.Value = b
+	}
+	return o, nil
+}
+
+func (o TypedOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Option:(%d): ", o.Code()))
+	buf.WriteString(fmt.Sprintf("Length: %d, ", len(o.Encode())))
+	name := o.Name
+	if name == "" {
+		name = o.Type.String()
+	}
+	if data, ok := o.Value.([]byte); ok {
+		buf.WriteString(fmt.Sprintf(" %s: %s", name, hex.EncodeToString(data)))
+	} else {
+		buf.WriteString(fmt.Sprintf(" %s: %v", name, o.Value))
+	}
+	return buf.String()
+}
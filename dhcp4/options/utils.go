@@ -2,6 +2,8 @@ package options
 
 import (
 	"bytes"
+	"fmt"
+	"net"
 )
 
 func Uint16ToBytes(data uint16) []byte {
@@ -27,8 +29,8 @@ func Uint8ToBytes(data uint8) []byte {
 }
 
 func BytesToUint16(data []byte) uint16 {
-	if len(data) != 2 {
-		return uint16(data[0])
+	if len(data) < 2 {
+		return 0
 	}
 	return uint16(data[0])<<8 | uint16(data[1])
 }
@@ -39,3 +41,52 @@ func BytesToUint32(data []byte) uint32 {
 	}
 	return uint32(data[0])<<24 | uint32(data[1])<<16 | uint32(data[2])<<8 | uint32(data[3])
 }
+
+// checkLength reports whether data is exactly n bytes long.
+func checkLength(data []byte, n int) error {
+	if len(data) != n {
+		return fmt.Errorf("%w: got %d, want %d", ErrOptionLength, len(data), n)
+	}
+	return nil
+}
+
+// checkMinLength reports whether data is at least n bytes long.
+func checkMinLength(data []byte, n int) error {
+	if len(data) < n {
+		return fmt.Errorf("%w: got %d, want at least %d", ErrOptionLength, len(data), n)
+	}
+	return nil
+}
+
+// checkMultiple reports whether data is a non-empty multiple of n bytes.
+func checkMultiple(data []byte, n int) error {
+	if len(data) == 0 || len(data)%n != 0 {
+		return fmt.Errorf("%w: got %d, want a multiple of %d", ErrOptionLength, len(data), n)
+	}
+	return nil
+}
+
+// decodeIPs splits data into IPv4 addresses, ignoring trailing bytes.
+func decodeIPs(data []byte) []net.IP {
+	ips := make([]net.IP, 0, len(data)/net.IPv4len)
+	for len(data) >= net.IPv4len {
+		ips = append(ips, net.IP(data[:net.IPv4len]))
+		data = data[net.IPv4len:]
+	}
+	return ips
+}
+
+func boolToBytes(value bool) []byte {
+	if value {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// bytesToBool decodes a one octet flag that must be 0 or 1.
+func bytesToBool(data []byte) (bool, error) {
+	if len(data) != 1 || data[0] > 1 {
+		return false, fmt.Errorf("invalid boolean value %x", data)
+	}
+	return data[0] == 1, nil
+}
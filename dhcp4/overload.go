@@ -0,0 +1,133 @@
+package dhcp4
+
+import (
+	"bytes"
+
+	L "github.com/lsongdev/dhcp-go/dhcp4/options"
+)
+
+// scanOverload appends the options carried in the file and sname fields
+// when the options field holds an option overload option (RFC 2132 9.3).
+// Options are concatenated in the order options, file, sname as RFC 3396
+// requires. The overload option itself is dropped since it only describes
+// the encoding; BytesWithLimit adds it back when needed. It may only
+// appear in the options field, so copies in file or sname are ignored.
+func (m *Message) scanOverload(raw []rawOption, data []byte) ([]rawOption, error) {
+	for i, r := range raw {
+		if r.code != L.OptionCodeOverload {
+			continue
+		}
+		option, err := L.DecodeOption(r.code, r.data)
+		if err != nil {
+			return nil, &DecodeError{Offset: r.offset, Err: err}
+		}
+		overload := option.(L.OverloadOption)
+		raw = append(raw[:i], raw[i+1:]...)
+		if overload.File() {
+			m.BootFileName = ""
+			if raw, err = scanOptions(raw, data[:headerLength], fileOffset); err != nil {
+				return nil, err
+			}
+		}
+		if overload.SName() {
+			m.ServerHostName = ""
+			if raw, err = scanOptions(raw, data[:fileOffset], snameOffset); err != nil {
+				return nil, err
+			}
+		}
+		return dropOption(raw, L.OptionCodeOverload), nil
+	}
+	return raw, nil
+}
+
+// dropOption removes code from raw.
+func dropOption(raw []rawOption, code L.OptionCode) []rawOption {
+	kept := raw[:0]
+	for _, r := range raw {
+		if r.code != code {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// optionField is an area of the message that options are packed into.
+type optionField struct {
+	buf  bytes.Buffer
+	size int
+}
+
+// free returns the space left for options, keeping one byte for the End
+// option.
+func (f *optionField) free() int {
+	return f.size - f.buf.Len() - 1
+}
+
+// overload packs options into the options field and, once it is full,
+// into the file and sname fields if the message does not use them. The
+// returned options field includes the option overload option but not the
+// End option; the file and sname fields are End terminated.
+func (m *Message) overload(options L.Options, maxSize int) (main *bytes.Buffer, sname, file []byte, err error) {
+	// Leave room for the option overload option in the options field.
+	fields := []*optionField{{size: maxSize - optionsOffset - 3}}
+	var fileField, snameField *optionField
+	if m.BootFileName == "" {
+		fileField = &optionField{size: headerLength - fileOffset}
+		fields = append(fields, fileField)
+	}
+	if m.ServerHostName == "" {
+		snameField = &optionField{size: fileOffset - snameOffset}
+		fields = append(fields, snameField)
+	}
+
+	i := 0
+	for _, option := range options {
+		data := option.Encode()
+		for first := true; first || len(data) > 0; first = false {
+			// An instance needs its code, length and at least one byte
+			// of data unless the option is empty.
+			need := 3
+			if len(data) == 0 {
+				need = 2
+			}
+			for i < len(fields) && fields[i].free() < need {
+				i++
+			}
+			if i == len(fields) {
+				return nil, nil, nil, ErrMessageTooLarge
+			}
+			n := fields[i].free() - 2
+			if n > len(data) {
+				n = len(data)
+			}
+			if n > maxOptionLength {
+				n = maxOptionLength
+			}
+			fields[i].buf.WriteByte(byte(option.Code()))
+			fields[i].buf.WriteByte(byte(n))
+			fields[i].buf.Write(data[:n])
+			data = data[n:]
+		}
+	}
+
+	var overload uint8
+	if fileField != nil && fileField.buf.Len() > 0 {
+		overload |= L.OverloadFile
+		fileField.buf.WriteByte(byte(L.OptionCodeEnd))
+		file = fileField.buf.Bytes()
+	} else {
+		file = []byte(m.BootFileName)
+	}
+	if snameField != nil && snameField.buf.Len() > 0 {
+		overload |= L.OverloadSName
+		snameField.buf.WriteByte(byte(L.OptionCodeEnd))
+		sname = snameField.buf.Bytes()
+	} else {
+		sname = []byte(m.ServerHostName)
+	}
+	main = &fields[0].buf
+	if overload != 0 {
+		writeOption(main, L.OptionCodeOverload, []byte{overload})
+	}
+	return main, sname, file, nil
+}
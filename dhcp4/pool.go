@@ -1,12 +1,23 @@
 package dhcp4
 
 import (
+	"encoding/hex"
 	"errors"
 	"net"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/lsongdev/dhcp-go/dhcp4/options"
 )
 
 type IPPool struct {
+	// LeaseTime is the lease time handed out for the pool's addresses.
+	// When set, the leases reported to leasequeries expire LeaseTime
+	// after they were last allocated or assigned; otherwise their
+	// expiry is not known.
+	LeaseTime time.Duration
+
 	mu       sync.RWMutex
 	network  *net.IPNet
 	start    uint32
@@ -14,6 +25,8 @@ type IPPool struct {
 	excluded map[uint32]bool
 	leased   map[uint32]string
 	macToIP  map[string]uint32
+	updated  map[uint32]time.Time
+	relay    map[uint32]options.RelayAgentInformationOption
 }
 
 type PoolLease struct {
@@ -50,14 +63,23 @@ func NewIPPool(network *net.IPNet, start, end net.IP, excluded []net.IP) (*IPPoo
 		excluded: excludedMap,
 		leased:   make(map[uint32]string),
 		macToIP:  make(map[string]uint32),
+		updated:  make(map[uint32]time.Time),
+		relay:    make(map[uint32]options.RelayAgentInformationOption),
 	}, nil
 }
 
+// Contains reports whether ip is on the pool's network. Handlers use it
+// with Message.EffectiveLinkAddress to pick the pool for a client.
+func (p *IPPool) Contains(ip net.IP) bool {
+	return ip != nil && p.network.Contains(ip)
+}
+
 func (p *IPPool) Allocate(mac string) (net.IP, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	if ip, ok := p.macToIP[mac]; ok {
+		p.updated[ip] = time.Now()
 		return uint32ToIP(ip), nil
 	}
 
@@ -70,6 +92,7 @@ func (p *IPPool) Allocate(mac string) (net.IP, error) {
 		}
 		p.leased[i] = mac
 		p.macToIP[mac] = i
+		p.updated[i] = time.Now()
 		return uint32ToIP(i), nil
 	}
 
@@ -94,9 +117,12 @@ func (p *IPPool) Assign(mac string, ip net.IP) error {
 	}
 	if current, ok := p.macToIP[mac]; ok && current != ipU32 {
 		delete(p.leased, current)
+		delete(p.updated, current)
+		delete(p.relay, current)
 	}
 	p.leased[ipU32] = mac
 	p.macToIP[mac] = ipU32
+	p.updated[ipU32] = time.Now()
 	return nil
 }
 
@@ -112,7 +138,21 @@ func (p *IPPool) Release(ipStr string) {
 	if mac, ok := p.leased[u32]; ok {
 		delete(p.macToIP, mac)
 		delete(p.leased, u32)
+		delete(p.updated, u32)
+		delete(p.relay, u32)
+	}
+}
+
+// AddressOf returns the address leased to mac.
+func (p *IPPool) AddressOf(mac string) (net.IP, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	ip, ok := p.macToIP[mac]
+	if !ok {
+		return nil, false
 	}
+	return uint32ToIP(ip), true
 }
 
 func (p *IPPool) IsLeased(ipStr string) bool {
@@ -138,6 +178,87 @@ func (p *IPPool) Leases() []PoolLease {
 	return leases
 }
 
+// Manages reports whether ip is an assignable address of the pool.
+func (p *IPPool) Manages(ip net.IP) bool {
+	u32 := ipToUint32(ip)
+	return ip.To4() != nil && u32 >= p.start && u32 <= p.end && !p.excluded[u32]
+}
+
+// LeaseByIP implements LeaseStore.
+func (p *IPPool) LeaseByIP(ip net.IP) (Lease, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	u32 := ipToUint32(ip)
+	key, ok := p.leased[u32]
+	if !ok || ip.To4() == nil {
+		return Lease{}, false
+	}
+	return p.lease(u32, key), true
+}
+
+// LeasesByHardwareAddr implements LeaseStore. It finds leases allocated
+// to the hardware address string or to the key Message.ClientID returns
+// for a client without a client identifier.
+func (p *IPPool) LeasesByHardwareAddr(addr net.HardwareAddr) []Lease {
+	return p.leasesByKey(addr.String(), "hw:"+addr.String())
+}
+
+// LeasesByClientID implements LeaseStore. It finds leases allocated to
+// the key Message.ClientID returns for a client identifier.
+func (p *IPPool) LeasesByClientID(id []byte) []Lease {
+	return p.leasesByKey("id:" + hex.EncodeToString(id))
+}
+
+// ActiveLeases implements BulkLeaseStore.
+func (p *IPPool) ActiveLeases() []Lease {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	leases := make([]Lease, 0, len(p.leased))
+	for u32, key := range p.leased {
+		leases = append(leases, p.lease(u32, key))
+	}
+	return leases
+}
+
+// RecordRelayAgentInformation implements RelayAgentRecorder.
+func (p *IPPool) RecordRelayAgentInformation(ip net.IP, info options.RelayAgentInformationOption) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	u32 := ipToUint32(ip)
+	if _, ok := p.leased[u32]; ok {
+		p.relay[u32] = info
+	}
+}
+
+func (p *IPPool) leasesByKey(keys ...string) []Lease {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var leases []Lease
+	for _, key := range keys {
+		if u32, ok := p.macToIP[key]; ok {
+			leases = append(leases, p.lease(u32, key))
+		}
+	}
+	return leases
+}
+
+func (p *IPPool) lease(u32 uint32, key string) Lease {
+	lease := Lease{
+		IP:                    uint32ToIP(u32).To4(),
+		LastTransaction:       p.updated[u32],
+		RelayAgentInformation: p.relay[u32],
+	}
+	if p.LeaseTime > 0 {
+		lease.Expires = lease.LastTransaction.Add(p.LeaseTime)
+	}
+	if strings.HasPrefix(key, "id:") {
+		lease.ClientID, _ = hex.DecodeString(strings.TrimPrefix(key, "id:"))
+	} else if mac, err := net.ParseMAC(strings.TrimPrefix(key, "hw:")); err == nil {
+		lease.HardwareAddr = mac
+	}
+	return lease
+}
+
 func ipToUint32(ip net.IP) uint32 {
 	ip = ip.To4()
 	if ip == nil {
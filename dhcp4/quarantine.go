@@ -0,0 +1,191 @@
+package dhcp4
+
+import (
+	"net"
+	"sync"
+
+	"github.com/lsongdev/dhcp-go/dhcp4/options"
+)
+
+// DefaultQuarantineLeaseTime is the lease time, in seconds, of
+// quarantine addresses when QuarantineLeaseTime is not set. It bounds
+// how long a registered device waits before it renews and moves to the
+// production pool.
+const DefaultQuarantineLeaseTime = 300
+
+// QuarantineHandler is a ServerMuxHandler for network access control.
+// Devices whose hardware address has not been registered get an address
+// from the Quarantine pool with a short lease and the captive portal
+// option (RFC 8910). Once Register marks a device, its next renewal of
+// the quarantine address is answered with a DHCPNAK, so that it starts
+// over and gets an address from the Production pool. Unregister moves a
+// device back the same way.
+type QuarantineHandler struct {
+	Production *IPPool
+	Quarantine *IPPool
+	// CaptivePortalURI is sent to quarantined devices.
+	CaptivePortalURI string
+	// LeaseTime is the lease time of production addresses in seconds.
+	// The default of NewAckMessage is kept when it is zero.
+	LeaseTime uint32
+	// QuarantineLeaseTime is the lease time of quarantine addresses in
+	// seconds; DefaultQuarantineLeaseTime when zero.
+	QuarantineLeaseTime uint32
+	// Options are sent to every device. QuarantineOptions are added for
+	// quarantined devices and replace Options with the same code, for
+	// example to hand out the portal's DNS server.
+	Options           []options.Option
+	QuarantineOptions []options.Option
+
+	mu         sync.RWMutex
+	registered map[string]bool
+}
+
+func NewQuarantineHandler(production, quarantine *IPPool, captivePortalURI string) *QuarantineHandler {
+	return &QuarantineHandler{
+		Production:       production,
+		Quarantine:       quarantine,
+		CaptivePortalURI: captivePortalURI,
+		registered:       make(map[string]bool),
+	}
+}
+
+// Register marks mac as registered. The device moves to the production
+// pool when it next renews its lease.
+func (h *QuarantineHandler) Register(mac string) error {
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.registered == nil {
+		h.registered = make(map[string]bool)
+	}
+	h.registered[hw.String()] = true
+	return nil
+}
+
+// Unregister returns mac to quarantine when it next renews its lease.
+func (h *QuarantineHandler) Unregister(mac string) error {
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.registered, hw.String())
+	return nil
+}
+
+// Registered reports whether mac has been registered.
+func (h *QuarantineHandler) Registered(mac string) bool {
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		return false
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.registered[hw.String()]
+}
+
+// pools returns the pool mac gets addresses from and the one it must not
+// hold a lease in.
+func (h *QuarantineHandler) pools(mac string) (pool, other *IPPool, quarantined bool) {
+	if h.Registered(mac) {
+		return h.Production, h.Quarantine, false
+	}
+	return h.Quarantine, h.Production, true
+}
+
+func (h *QuarantineHandler) responseOptions(quarantined bool) []options.Option {
+	responseOptions := append([]options.Option(nil), h.Options...)
+	if !quarantined {
+		if h.LeaseTime != 0 {
+			responseOptions = append(responseOptions, options.NewLeaseTimeOption(h.LeaseTime))
+		}
+		return responseOptions
+	}
+	leaseTime := h.QuarantineLeaseTime
+	if leaseTime == 0 {
+		leaseTime = DefaultQuarantineLeaseTime
+	}
+	responseOptions = append(responseOptions, options.NewLeaseTimeOption(leaseTime))
+	if h.CaptivePortalURI != "" {
+		responseOptions = append(responseOptions, options.NewCaptivePortalOption(h.CaptivePortalURI))
+	}
+	return append(responseOptions, h.QuarantineOptions...)
+}
+
+// releaseLease drops the lease mac holds in pool, if any.
+func releaseLease(pool *IPPool, mac string) {
+	if ip, ok := pool.AddressOf(mac); ok {
+		pool.Release(ip.String())
+	}
+}
+
+// releaseOwned drops the lease on ip if mac holds it in either pool.
+func (h *QuarantineHandler) releaseOwned(mac, ip string) {
+	for _, pool := range []*IPPool{h.Production, h.Quarantine} {
+		if leased, ok := pool.AddressOf(mac); ok && leased.String() == ip {
+			pool.Release(ip)
+		}
+	}
+}
+
+// HandleDiscover implements ServerMuxHandler.
+func (h *QuarantineHandler) HandleDiscover(request *Message, rw OfferWriter) {
+	mac := request.GetMacAddress()
+	pool, other, quarantined := h.pools(mac)
+	releaseLease(other, mac)
+	ip, err := pool.Allocate(mac)
+	if err != nil {
+		return
+	}
+	if rc, ok := rw.(RapidCommitWriter); ok && rc.RapidCommit() {
+		rc.SendRapidCommitAck(ip.String(), h.responseOptions(quarantined)...)
+		return
+	}
+	rw.SendOffer(ip.String(), h.responseOptions(quarantined)...)
+}
+
+// HandleRequest implements ServerMuxHandler.
+func (h *QuarantineHandler) HandleRequest(request IGetRequestedIP, rw AckWriter) {
+	mac := request.GetMacAddress()
+	pool, other, quarantined := h.pools(mac)
+	releaseLease(other, mac)
+	ip, err := pool.Allocate(mac)
+	if err != nil {
+		rw.SendNak("no address available")
+		return
+	}
+	if requested := request.GetRequestedIP(); requested != "" && requested != ip.String() {
+		rw.SendNak("requested address not available")
+		return
+	}
+	rw.SendAck(ip.String(), h.responseOptions(quarantined)...)
+}
+
+// HandleRenew implements ServerMuxHandler. A device renewing an address
+// from the pool it no longer belongs to is sent a DHCPNAK.
+func (h *QuarantineHandler) HandleRenew(request IGetClientIP, rw AckWriter) {
+	mac := request.GetMacAddress()
+	ip := request.GetClientIP()
+	pool, other, quarantined := h.pools(mac)
+	if leased, ok := pool.AddressOf(mac); !ok || leased.String() != ip {
+		releaseLease(other, mac)
+		rw.SendNak("address not valid for this client")
+		return
+	}
+	rw.SendAck(ip, h.responseOptions(quarantined)...)
+}
+
+// HandleRelease implements ServerMuxHandler.
+func (h *QuarantineHandler) HandleRelease(request IGetClientIP, rw ResponseWriter) {
+	h.releaseOwned(request.GetMacAddress(), request.GetClientIP())
+}
+
+// HandleDecline implements ServerMuxHandler.
+func (h *QuarantineHandler) HandleDecline(request IGetRequestedIP, rw ResponseWriter) {
+	h.releaseOwned(request.GetMacAddress(), request.GetRequestedIP())
+}
@@ -6,6 +6,7 @@ import (
 	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/lsongdev/dhcp-go/dhcp4/options"
 )
@@ -50,10 +51,46 @@ type Server struct {
 	ClientPort int
 	Handler    Handler
 
-	mu       sync.RWMutex
-	conn     *net.UDPConn
-	closed   bool
-	requests atomic.Uint64
+	// AlwaysSend lists option codes included in replies even when the
+	// client did not ask for them in its parameter request list.
+	AlwaysSend []options.OptionCode
+	// IgnoreParameterRequestList sends every option the handler sets
+	// instead of only those the client requested.
+	IgnoreParameterRequestList bool
+	// InterfaceAddr is the address of the interface the server serves
+	// directly attached clients on. It is copied into each request's
+	// InterfaceAddr; when unset, the connection's local address is used
+	// if it is not a wildcard.
+	InterfaceAddr net.IP
+	// Authenticator, when set, requires delayed authentication
+	// (RFC 3118): requests without a valid authentication option are
+	// dropped and replies are signed. This includes bulk leasequeries,
+	// whose connection is closed on the first unauthenticated query.
+	Authenticator *Authenticator
+	// ForceRenewNonce gives clients that support it a nonce in each
+	// DHCPACK (RFC 6704), so that ForceRenew can send them authenticated
+	// DHCPFORCERENEW messages. It is ignored when Authenticator is set.
+	ForceRenewNonce bool
+	// BulkLeaseQueryTimeout closes a bulk leasequery connection that
+	// sends nothing for that long; DefaultBulkLeaseQueryTimeout when
+	// zero.
+	BulkLeaseQueryTimeout time.Duration
+	// MaxBulkLeaseQueryConns limits the bulk leasequery connections
+	// served at once; DefaultMaxBulkLeaseQueryConns when zero. Further
+	// connections are closed as they are accepted.
+	MaxBulkLeaseQueryConns int
+
+	mu        sync.RWMutex
+	conn      *net.UDPConn
+	closed    bool
+	requests  atomic.Uint64
+	malformed atomic.Uint64
+	// unauthenticated counts requests dropped by the Authenticator.
+	unauthenticated atomic.Uint64
+	forceRenew      *forceRenewLeases
+	// listener and bulkConns serve bulk leasequery over TCP.
+	listener  net.Listener
+	bulkConns map[net.Conn]struct{}
 }
 
 func NewServer(addr string, handler Handler) *Server {
@@ -92,6 +129,10 @@ func (s *Server) Serve(conn *net.UDPConn) error {
 	}
 	s.conn = conn
 	s.closed = false
+	if s.ForceRenewNonce && s.Authenticator == nil && s.forceRenew == nil {
+		s.forceRenew = newForceRenewLeases()
+	}
+	forceRenew := s.forceRenew
 	s.mu.Unlock()
 
 	defer func() {
@@ -103,9 +144,16 @@ func (s *Server) Serve(conn *net.UDPConn) error {
 		_ = conn.Close()
 	}()
 
+	interfaceAddr := s.InterfaceAddr
+	if interfaceAddr == nil {
+		if local, ok := conn.LocalAddr().(*net.UDPAddr); ok && !local.IP.IsUnspecified() {
+			interfaceAddr = local.IP
+		}
+	}
+
 	buf := make([]byte, 4096)
 	for {
-		n, _, err := conn.ReadFromUDP(buf)
+		n, peer, err := conn.ReadFromUDP(buf)
 		if err != nil {
 			s.mu.RLock()
 			closed := s.closed
@@ -116,14 +164,41 @@ func (s *Server) Serve(conn *net.UDPConn) error {
 			return fmt.Errorf("dhcp4: read request: %w", err)
 		}
 		request, err := FromBytes(buf[:n])
-		if err != nil || request.OpCode != OpCodeBootRequest {
+		if err != nil {
+			s.malformed.Add(1)
+			continue
+		}
+		if request.OpCode != OpCodeBootRequest {
 			continue
 		}
+		if request.MessageType() == options.DHCPBULKLEASEQUERY {
+			// Bulk leasequery is only served over TCP (RFC 6926 section
+			// 6.1), where the requestor is connected.
+			continue
+		}
+		if s.Authenticator != nil {
+			if err := s.Authenticator.Verify(buf[:n], replayPeer(request, peer.IP)); err != nil {
+				s.unauthenticated.Add(1)
+				continue
+			}
+		}
 		s.requests.Add(1)
+		request.InterfaceAddr = interfaceAddr
+		if forceRenew != nil {
+			switch request.MessageType() {
+			case options.DHCPRELEASE, options.DHCPDECLINE:
+				forceRenew.release(request)
+			}
+		}
 		rw := &responseWriter{
 			conn:       conn,
 			request:    request,
+			peer:       peer,
 			clientPort: s.clientPort(),
+			alwaysSend: s.AlwaysSend,
+			filter:     !s.IgnoreParameterRequestList,
+			auth:       s.Authenticator,
+			forceRenew: forceRenew,
 		}
 		go s.Handler.ServeDHCP(request, rw)
 	}
@@ -140,7 +215,11 @@ func (s *Server) Close() error {
 	s.mu.Lock()
 	s.closed = true
 	conn := s.conn
+	listener := s.listener
 	s.mu.Unlock()
+	if listener != nil {
+		_ = listener.Close()
+	}
 	if conn == nil {
 		return nil
 	}
@@ -164,6 +243,18 @@ func (s *Server) RequestCount() uint64 {
 	return s.requests.Load()
 }
 
+// MalformedCount returns the number of packets dropped because they could
+// not be decoded.
+func (s *Server) MalformedCount() uint64 {
+	return s.malformed.Load()
+}
+
+// UnauthenticatedCount returns the number of requests dropped because
+// they failed authentication.
+func (s *Server) UnauthenticatedCount() uint64 {
+	return s.unauthenticated.Load()
+}
+
 func ListenAndServe(addr string, handler Handler) error {
 	err := NewServer(addr, handler).ListenAndServe()
 	if errors.Is(err, ErrServerClosed) {
@@ -175,25 +266,121 @@ func ListenAndServe(addr string, handler Handler) error {
 type responseWriter struct {
 	conn       *net.UDPConn
 	request    *Message
+	peer       *net.UDPAddr
 	clientPort int
+	alwaysSend []options.OptionCode
+	filter     bool
+	auth       *Authenticator
+	forceRenew *forceRenewLeases
 }
 
 func (w *responseWriter) WriteResponse(resp *Message, responseOptions ...options.Option) error {
 	resp.OpCode = OpCodeBootReply
 	resp.Xid = w.request.Xid
 	applyResponseOptions(resp, responseOptions)
+	if w.filter {
+		filterResponseOptions(resp, w.request, w.alwaysSend)
+	}
+	echoSubnetSelection(resp, w.request)
+	if !isRequestorReply(resp.MessageType()) {
+		// Leasequery replies carry the lease's relay agent information
+		// instead (RFC 4388 section 6.4.2).
+		echoRelayAgentInformation(resp, w.request)
+	}
+	if w.auth != nil {
+		w.auth.prepare(resp)
+	} else if w.forceRenew != nil && resp.MessageType() == options.DHCPACK &&
+		!resp.YourIPAddr.Equal(net.IPv4zero) && supportsForceRenewNonce(w.request) {
+		option, err := w.forceRenew.bind(w.request, resp)
+		if err != nil {
+			return err
+		}
+		resp.SetOption(option)
+	}
 
 	broadcastFlag := (w.request.Flags & 0x8000) != 0
 	var addr net.UDPAddr
-	if broadcastFlag || resp.YourIPAddr.Equal(net.IPv4zero) {
+	if isRequestorReply(resp.MessageType()) && w.peer != nil {
+		// Leasequery replies go back to the requestor (RFC 4388 section 6.4)
+		addr = *w.peer
+	} else if broadcastFlag || resp.YourIPAddr.Equal(net.IPv4zero) {
 		addr = net.UDPAddr{IP: net.IPv4bcast, Port: w.clientPort}
 	} else {
 		addr = net.UDPAddr{IP: resp.YourIPAddr, Port: w.clientPort}
 	}
-	_, err := w.conn.WriteTo(resp.Bytes(), &addr)
+	data, err := encodeReply(resp, w.request.MaxMessageSize())
+	if err != nil {
+		return err
+	}
+	if w.auth != nil {
+		if data, err = w.auth.sign(data); err != nil {
+			return err
+		}
+	}
+	_, err = w.conn.WriteTo(data, &addr)
 	return err
 }
 
+// requiredReplyOptions are never dropped to make a reply fit.
+var requiredReplyOptions = map[options.OptionCode]bool{
+	options.OptionCodeMessageType:           true,
+	options.OptionCodeServerIdentifier:      true,
+	options.OptionCodeLeaseTime:             true,
+	options.OptionCodeMessage:               true,
+	options.OptionCodeRelayAgentInformation: true,
+	options.OptionCodeSubnetSelection:       true,
+	options.OptionCodeAuthentication:        true,
+	options.OptionCodeStatusCode:            true,
+}
+
+// echoSubnetSelection returns the client's subnet selection option
+// unchanged, whether or not it was requested (RFC 3011 section 3).
+func echoSubnetSelection(resp, req *Message) {
+	if option := req.GetOption(options.OptionCodeSubnetSelection); option != nil {
+		resp.SetOption(option)
+	}
+}
+
+// echoRelayAgentInformation copies the relay agent information option of
+// req unchanged as the last option of resp (RFC 3046 section 2.2).
+func echoRelayAgentInformation(resp, req *Message) {
+	resp.Options.Del(options.OptionCodeRelayAgentInformation)
+	if option := req.GetOption(options.OptionCodeRelayAgentInformation); option != nil {
+		resp.Options.Add(option)
+	}
+}
+
+// encodeReply encodes resp in at most maxSize bytes. When the options do
+// not fit even with overloading, optional options are dropped starting
+// from the last one, so options should be ordered by importance. Short
+// replies are padded to the minimum BOOTP message size.
+func encodeReply(resp *Message, maxSize int) ([]byte, error) {
+	for {
+		data, err := resp.BytesWithLimit(maxSize)
+		if err == nil {
+			if len(data) < minBOOTPMessageSize {
+				data = append(data, make([]byte, minBOOTPMessageSize-len(data))...)
+			}
+			return data, nil
+		}
+		if !errors.Is(err, ErrMessageTooLarge) || !dropOptionalOption(resp) {
+			return nil, err
+		}
+	}
+}
+
+// dropOptionalOption removes the last option that is not required and
+// reports whether one was found.
+func dropOptionalOption(resp *Message) bool {
+	for i := len(resp.Options) - 1; i >= 0; i-- {
+		if !requiredReplyOptions[resp.Options[i].Code()] {
+			resp.Options = append(resp.Options[:i], resp.Options[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 func applyResponseOptions(resp *Message, responseOptions []options.Option) {
 	for _, option := range responseOptions {
 		resp.SetOption(option)
@@ -201,6 +388,62 @@ func applyResponseOptions(resp *Message, responseOptions []options.Option) {
 			resp.ServerIPAddr = serverID.ServerIdentifier
 		}
 	}
+	applyBootOptions(resp)
+}
+
+// applyBootOptions copies the network boot options into the siaddr and
+// file header fields, which older PXE clients read instead of options.
+func applyBootOptions(resp *Message) {
+	if option, ok := resp.GetOption(options.OptionCodeTFTPServerAddress).(options.TFTPServerAddressOption); ok && len(option.Servers) > 0 {
+		resp.ServerIPAddr = option.Servers[0]
+	} else if option, ok := resp.GetOption(options.OptionCodeTFTPServerName).(options.TFTPServerNameOption); ok {
+		if ip := net.ParseIP(option.ServerName).To4(); ip != nil {
+			resp.ServerIPAddr = ip
+		}
+	}
+	if option, ok := resp.GetOption(options.OptionCodeBootFileName).(options.BootFileNameOption); ok {
+		resp.BootFileName = option.BootFileName
+	}
+}
+
+// echoedReplyOptions are answers to options the client sent, so they are
+// kept whenever the client included the option in its request.
+var echoedReplyOptions = map[options.OptionCode]bool{
+	options.OptionCodeClientFullyQualifiedDomainName: true,
+	options.OptionCodeRapidCommit:                    true,
+}
+
+// filterResponseOptions keeps the options the client asked for in its
+// parameter request list, in the client's order, followed by required
+// options, answers to options the client sent and those listed in
+// alwaysSend. Replies to clients without a
+// parameter request list are left unchanged (RFC 2131 section 4.3.1).
+func filterResponseOptions(resp, req *Message, alwaysSend []options.OptionCode) {
+	prl, ok := req.GetOption(options.OptionCodeParameterRequest).(options.ParameterRequestOption)
+	if !ok {
+		return
+	}
+	requested := make(map[options.OptionCode]bool, len(prl.Parameters))
+	filtered := make(options.Options, 0, len(resp.Options))
+	for _, code := range prl.Parameters {
+		if requested[code] {
+			continue
+		}
+		requested[code] = true
+		filtered = append(filtered, resp.Options.GetAll(code)...)
+	}
+	always := make(map[options.OptionCode]bool, len(alwaysSend))
+	for _, code := range alwaysSend {
+		always[code] = true
+	}
+	for _, option := range resp.Options {
+		code := option.Code()
+		echoed := echoedReplyOptions[code] && req.Options.Has(code)
+		if !requested[code] && (requiredReplyOptions[code] || echoed || always[code]) {
+			filtered = append(filtered, option)
+		}
+	}
+	resp.Options = filtered
 }
 
 func (w *responseWriter) SendOffer(ip string, responseOptions ...options.Option) {
@@ -14,8 +14,39 @@ type ServerMuxHandler interface {
 	HandleDecline(request IGetRequestedIP, rw ResponseWriter)
 }
 
+// RapidCommitWriter is passed to HandleDiscover so a handler can commit a
+// lease at once when the client asked for rapid commit (RFC 4039).
+type RapidCommitWriter interface {
+	OfferWriter
+	// RapidCommit reports whether the client asked for rapid commit and
+	// the server allows it.
+	RapidCommit() bool
+	// SendRapidCommitAck acknowledges the DHCPDISCOVER with a DHCPACK.
+	// It sends an offer instead when RapidCommit is false.
+	SendRapidCommitAck(ip string, options ...options.Option)
+}
+
 type DefaultServerMux struct {
 	h ServerMuxHandler
+
+	// RapidCommit allows handlers to answer a DHCPDISCOVER carrying the
+	// rapid commit option with a DHCPACK.
+	RapidCommit bool
+
+	// IPv6Mostly marks the served pool as IPv6-mostly (RFC 8925).
+	// DHCPDISCOVER and DHCPREQUEST messages that request the IPv6-only
+	// preferred option are answered without an address and never reach
+	// the handler.
+	IPv6Mostly bool
+	// V6OnlyWait is the V6ONLY_WAIT value, in seconds, sent in the
+	// IPv6-only preferred option.
+	V6OnlyWait uint32
+
+	// Leases answers DHCPLEASEQUERY messages (RFC 4388). Leasequeries
+	// are dropped when it is nil. It also answers DHCPBULKLEASEQUERY
+	// (RFC 6926) when it is a BulkLeaseStore; Server only passes those
+	// on from ServeBulkLeaseQuery connections.
+	Leases LeaseStore
 }
 
 func NewDefaultServerMux(h ServerMuxHandler) *DefaultServerMux {
@@ -26,9 +57,17 @@ func NewDefaultServerMux(h ServerMuxHandler) *DefaultServerMux {
 
 func (d *DefaultServerMux) ServeDHCP(req *Message, rw ResponseWriter) {
 	// log.Println("Received:", req.MessageType(), req.String())
+	if d.serveIPv6Only(req, rw) {
+		return
+	}
+	rw = d.recordRelayAgentInformation(req, rw)
 	switch req.MessageType() {
 	case options.DHCPDISCOVER:
-		d.h.HandleDiscover(req, rw)
+		d.h.HandleDiscover(req, &rapidCommitWriter{
+			ResponseWriter: rw,
+			request:        req,
+			allowed:        d.RapidCommit && req.RapidCommit(),
+		})
 	case options.DHCPREQUEST:
 		if req.ClientIPAddr.Equal(net.IPv4zero) {
 			d.h.HandleRequest(req, rw)
@@ -39,7 +78,47 @@ func (d *DefaultServerMux) ServeDHCP(req *Message, rw ResponseWriter) {
 		d.h.HandleDecline(req, rw)
 	case options.DHCPRELEASE:
 		d.h.HandleRelease(req, rw)
+	case options.DHCPLEASEQUERY:
+		d.serveLeaseQuery(req, rw)
+	case options.DHCPBULKLEASEQUERY:
+		d.serveBulkLeaseQuery(req, rw)
+	}
+}
+
+// serveIPv6Only answers clients that prefer IPv6-only operation on an
+// IPv6-mostly pool without allocating an address (RFC 8925 section 3.3).
+func (d *DefaultServerMux) serveIPv6Only(req *Message, rw ResponseWriter) bool {
+	if !d.IPv6Mostly || !req.RequestsOption(options.OptionCodeIPv6OnlyPreferred) {
+		return false
+	}
+	option := options.NewIPv6OnlyPreferredOption(d.V6OnlyWait)
+	switch req.MessageType() {
+	case options.DHCPDISCOVER:
+		rw.SendOffer(net.IPv4zero.String(), option)
+	case options.DHCPREQUEST:
+		rw.SendAck(net.IPv4zero.String(), option)
+	default:
+		return false
+	}
+	return true
+}
+
+type rapidCommitWriter struct {
+	ResponseWriter
+	request *Message
+	allowed bool
+}
+
+func (w *rapidCommitWriter) RapidCommit() bool {
+	return w.allowed
+}
+
+func (w *rapidCommitWriter) SendRapidCommitAck(ip string, responseOptions ...options.Option) {
+	if !w.allowed {
+		w.SendOffer(ip, responseOptions...)
+		return
 	}
+	_ = w.WriteResponse(NewRapidCommitAckMessage(w.request, ip), responseOptions...)
 }
 
 // func handleDiscover(req *Message) *Message {
@@ -1,7 +1,10 @@
 package dhcp4
 
 import (
+	"bytes"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"net"
 	"testing"
 	"time"
@@ -100,3 +103,409 @@ func TestResponseWriterCopiesServerIdentifier(t *testing.T) {
 		t.Fatalf("server IP = %s, want 192.0.2.1", got)
 	}
 }
+
+func TestEncodeReplyFitsClientLimit(t *testing.T) {
+	req := NewDiscoverMessage()
+	resp := NewOfferMessage(req, "192.0.2.10")
+	resp.SetServerIP("192.0.2.1")
+	resp.BootFileName = "pxelinux.0"
+	resp.ServerHostName = "boot"
+	applyResponseOptions(resp, []options.Option{options.NewServerIdentifierOption("192.0.2.1")})
+	for i := 0; i < 40; i++ {
+		resp.AddOption(options.NewRawOption(options.OptionCode(224 + i%30)).Decode(make([]byte, 20)))
+	}
+	data, err := encodeReply(resp, req.MaxMessageSize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > 576-28 {
+		t.Fatalf("len = %d, want <= %d", len(data), 576-28)
+	}
+	decoded, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []options.OptionCode{53, 54, 51} {
+		if decoded.GetOption(code) == nil {
+			t.Errorf("required option %d dropped", code)
+		}
+	}
+}
+
+func TestEncodeReplyPadsShortReplies(t *testing.T) {
+	resp := NewNakMessage(NewRequestMessage(), "no")
+	data, err := encodeReply(resp, NewRequestMessage().MaxMessageSize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != minBOOTPMessageSize {
+		t.Fatalf("len = %d, want %d", len(data), minBOOTPMessageSize)
+	}
+	if _, err := FromBytes(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFilterResponseOptionsFollowsParameterRequestList(t *testing.T) {
+	req := NewDiscoverMessage()
+	req.SetOption(options.NewParameterRequestOption([]options.OptionCode{
+		options.OptionCodeDomainNameServer,
+		options.OptionCodeSubnetMask,
+		options.OptionCodeRouter,
+	}))
+	resp := NewOfferMessage(req, "192.0.2.10")
+	applyResponseOptions(resp, []options.Option{
+		options.NewSubnetMaskOption("255.255.255.0"),
+		options.NewRouterOption([]string{"192.0.2.1"}),
+		options.NewDomainNameOption("example.com"),
+		options.NewNetworkTimeProtocolServersOption([]string{"192.0.2.5"}),
+		options.NewDomainNameServerOption([]string{"192.0.2.53"}),
+	})
+	filterResponseOptions(resp, req, []options.OptionCode{options.OptionCodeNetworkTimeProtocolServers})
+	var codes []options.OptionCode
+	for _, option := range resp.Options {
+		codes = append(codes, option.Code())
+	}
+	want := []options.OptionCode{6, 1, 3, 53, 51, 42}
+	if fmt.Sprint(codes) != fmt.Sprint(want) {
+		t.Fatalf("codes = %v, want %v", codes, want)
+	}
+}
+
+func TestResponseEchoesRelayAgentInformation(t *testing.T) {
+	req := NewDiscoverMessage()
+	req.GatewayIPAddr = net.ParseIP("192.0.2.1")
+	req.SetOption(options.NewRelayAgentInformationOption(
+		options.NewCircuitIDSubOption([]byte("eth0/1")),
+		options.NewRemoteIDSubOption([]byte("switch-7")),
+	))
+	req.SetOption(options.NewParameterRequestOption([]options.OptionCode{options.OptionCodeSubnetMask}))
+	req, err := FromBytes(req.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(req.CircuitID()) != "eth0/1" || string(req.RemoteID()) != "switch-7" {
+		t.Fatalf("circuit %q remote %q", req.CircuitID(), req.RemoteID())
+	}
+	resp := NewOfferMessage(req, "192.0.2.10")
+	applyResponseOptions(resp, []options.Option{options.NewSubnetMaskOption("255.255.255.0")})
+	filterResponseOptions(resp, req, nil)
+	echoRelayAgentInformation(resp, req)
+	last := resp.Options[len(resp.Options)-1]
+	if last.Code() != options.OptionCodeRelayAgentInformation {
+		t.Fatalf("last option = %d, want 82", last.Code())
+	}
+	if !bytes.Equal(last.Encode(), req.GetOption(options.OptionCodeRelayAgentInformation).Encode()) {
+		t.Fatal("relay agent information changed")
+	}
+}
+
+type recordingWriter struct {
+	request   *Message
+	responses []*Message
+}
+
+func (w *recordingWriter) SendOffer(ip string, responseOptions ...options.Option) {
+	_ = w.WriteResponse(NewOfferMessage(w.request, ip), responseOptions...)
+}
+
+func (w *recordingWriter) SendAck(ip string, responseOptions ...options.Option) {
+	_ = w.WriteResponse(NewAckMessage(w.request, ip), responseOptions...)
+}
+
+func (w *recordingWriter) SendNak(reason string, responseOptions ...options.Option) {
+	_ = w.WriteResponse(NewNakMessage(w.request, reason), responseOptions...)
+}
+
+func (w *recordingWriter) WriteResponse(resp *Message, responseOptions ...options.Option) error {
+	applyResponseOptions(resp, responseOptions)
+	w.responses = append(w.responses, resp)
+	return nil
+}
+
+type rapidCommitHandler struct {
+	ServerMuxHandler
+}
+
+func (h rapidCommitHandler) HandleDiscover(request *Message, rw OfferWriter) {
+	if rc, ok := rw.(RapidCommitWriter); ok && rc.RapidCommit() {
+		rc.SendRapidCommitAck("192.0.2.10")
+		return
+	}
+	rw.SendOffer("192.0.2.10")
+}
+
+func TestDefaultServerMuxRapidCommit(t *testing.T) {
+	tests := []struct {
+		name    string
+		allow   bool
+		request bool
+		want    options.MessageType
+	}{
+		{"allowed", true, true, options.DHCPACK},
+		{"not requested", true, false, options.DHCPOFFER},
+		{"not allowed", false, true, options.DHCPOFFER},
+	}
+	for _, tt := range tests {
+		req := NewDiscoverMessage()
+		if tt.request {
+			req.SetOption(options.NewRapidCommitOption())
+		}
+		mux := NewDefaultServerMux(rapidCommitHandler{})
+		mux.RapidCommit = tt.allow
+		rw := &recordingWriter{request: req}
+		mux.ServeDHCP(req, rw)
+		if len(rw.responses) != 1 {
+			t.Fatalf("%s: responses = %d, want 1", tt.name, len(rw.responses))
+		}
+		resp := rw.responses[0]
+		if resp.MessageType() != tt.want {
+			t.Errorf("%s: message type = %s, want %s", tt.name, resp.MessageType(), tt.want)
+		}
+		if resp.RapidCommit() != (tt.want == options.DHCPACK) {
+			t.Errorf("%s: rapid commit option = %v", tt.name, resp.RapidCommit())
+		}
+	}
+}
+
+func TestDefaultServerMuxIPv6OnlyPreferred(t *testing.T) {
+	for _, requested := range []bool{true, false} {
+		req := NewDiscoverMessage()
+		req.SetOption(options.NewParameterRequestOption([]options.OptionCode{options.OptionCodeSubnetMask}))
+		if requested {
+			req.RequestOption(options.OptionCodeIPv6OnlyPreferred)
+		}
+		mux := NewDefaultServerMux(rapidCommitHandler{})
+		mux.IPv6Mostly = true
+		mux.V6OnlyWait = 1800
+		rw := &recordingWriter{request: req}
+		mux.ServeDHCP(req, rw)
+		if len(rw.responses) != 1 {
+			t.Fatalf("requested %v: responses = %d, want 1", requested, len(rw.responses))
+		}
+		resp := rw.responses[0]
+		option, ok := resp.IPv6OnlyPreferred()
+		if ok != requested {
+			t.Fatalf("requested %v: IPv6-only preferred option sent = %v", requested, ok)
+		}
+		if !requested {
+			continue
+		}
+		if option.V6OnlyWait != 1800 || !resp.YourIPAddr.Equal(net.IPv4zero) {
+			t.Fatalf("wait = %d, yiaddr = %s", option.V6OnlyWait, resp.YourIPAddr)
+		}
+	}
+}
+
+func TestApplyResponseOptionsSetsBootFields(t *testing.T) {
+	resp := NewOfferMessage(NewDiscoverMessage(), "192.0.2.10")
+	applyResponseOptions(resp, []options.Option{
+		options.NewServerIdentifierOption("192.0.2.1"),
+		options.NewTFTPServerAddressOption([]string{"192.0.2.5"}),
+		options.NewBootFileNameOption("grubx64.efi"),
+	})
+	if !resp.ServerIPAddr.Equal(net.ParseIP("192.0.2.5")) || resp.BootFileName != "grubx64.efi" {
+		t.Fatalf("siaddr %s file %q", resp.ServerIPAddr, resp.BootFileName)
+	}
+}
+
+func TestResponseEchoesSubnetSelection(t *testing.T) {
+	req := NewDiscoverMessage()
+	req.SetOption(options.NewSubnetSelectionOption("203.0.113.0"))
+	req.SetOption(options.NewParameterRequestOption([]options.OptionCode{options.OptionCodeSubnetMask}))
+	resp := NewOfferMessage(req, "203.0.113.10")
+	filterResponseOptions(resp, req, nil)
+	echoSubnetSelection(resp, req)
+	if subnet := resp.SubnetSelection(); !subnet.Equal(net.ParseIP("203.0.113.0")) {
+		t.Fatalf("subnet selection = %v", subnet)
+	}
+}
+
+func TestDefaultServerMuxLeaseQuery(t *testing.T) {
+	_, network, _ := net.ParseCIDR("192.0.2.0/24")
+	pool, err := NewIPPool(network, net.ParseIP("192.0.2.10"), net.ParseIP("192.0.2.20"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Allocate("00:11:22:33:44:55"); err != nil {
+		t.Fatal(err)
+	}
+	clientID := options.NewClientIdentifierOption([]byte("router-7"), 0)
+	if _, err := pool.Allocate("id:" + hex.EncodeToString(clientID.Encode())); err != nil {
+		t.Fatal(err)
+	}
+	mux := NewDefaultServerMux(rapidCommitHandler{})
+	mux.Leases = pool
+
+	tests := []struct {
+		name  string
+		query *Message
+		want  options.MessageType
+		ip    string
+	}{
+		{"active ip", NewLeaseQueryByIPMessage("192.0.2.10"), options.DHCPLEASEACTIVE, "192.0.2.10"},
+		{"unassigned ip", NewLeaseQueryByIPMessage("192.0.2.15"), options.DHCPLEASEUNASSIGNED, "192.0.2.15"},
+		{"unknown ip", NewLeaseQueryByIPMessage("198.51.100.1"), options.DHCPLEASEUNKNOWN, "0.0.0.0"},
+		{"active mac", NewLeaseQueryByMacMessage("00:11:22:33:44:55"), options.DHCPLEASEACTIVE, "192.0.2.10"},
+		{"unknown mac", NewLeaseQueryByMacMessage("00:11:22:33:44:66"), options.DHCPLEASEUNKNOWN, "0.0.0.0"},
+		{"active client id", NewLeaseQueryByClientIDMessage(clientID), options.DHCPLEASEACTIVE, "192.0.2.11"},
+	}
+	for _, tt := range tests {
+		rw := &recordingWriter{request: tt.query}
+		mux.ServeDHCP(tt.query, rw)
+		if len(rw.responses) != 1 {
+			t.Fatalf("%s: responses = %d, want 1", tt.name, len(rw.responses))
+		}
+		reply, err := FromBytes(rw.responses[0].Bytes())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if reply.MessageType() != tt.want || reply.ClientIPAddr.String() != tt.ip {
+			t.Fatalf("%s: got %s ciaddr %s, want %s ciaddr %s", tt.name, reply.MessageType(), reply.ClientIPAddr, tt.want, tt.ip)
+		}
+		if tt.want != options.DHCPLEASEACTIVE {
+			continue
+		}
+		if _, ok := reply.ClientLastTransactionTime(); !ok {
+			t.Fatalf("%s: missing client last transaction time", tt.name)
+		}
+	}
+
+	reply := newLeaseQueryReply(NewLeaseQueryByMacMessage("00:11:22:33:44:55"), multiLeaseStore{}, time.Now())
+	if reply.ClientIPAddr.String() != "192.0.2.31" || len(reply.AssociatedIPs()) != 2 {
+		t.Fatalf("ciaddr %s associated %v", reply.ClientIPAddr, reply.AssociatedIPs())
+	}
+}
+
+func TestLeaseQueryReplyCarriesLeaseRelayAgentInformation(t *testing.T) {
+	_, network, _ := net.ParseCIDR("192.0.2.0/24")
+	pool, err := NewIPPool(network, net.ParseIP("192.0.2.10"), net.ParseIP("192.0.2.20"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.LeaseTime = time.Hour
+	ip, err := pool.Allocate("00:11:22:33:44:55")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaseInfo := options.NewRelayAgentInformationOption(options.NewCircuitIDSubOption([]byte("eth0/1")))
+	pool.RecordRelayAgentInformation(ip, leaseInfo.(options.RelayAgentInformationOption))
+	mux := NewDefaultServerMux(rapidCommitHandler{})
+	mux.Leases = pool
+
+	serverConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serverConn.Close()
+	requestor, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer requestor.Close()
+
+	query := NewLeaseQueryByIPMessage(ip.String())
+	query.GatewayIPAddr = net.ParseIP("127.0.0.1")
+	query.SetOption(options.NewRelayAgentInformationOption(options.NewCircuitIDSubOption([]byte("requestor"))))
+	query, err = FromBytes(query.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rw := &responseWriter{
+		conn:       serverConn,
+		request:    query,
+		peer:       requestor.LocalAddr().(*net.UDPAddr),
+		clientPort: 68,
+		filter:     true,
+	}
+	mux.ServeDHCP(query, rw)
+
+	buf := make([]byte, 1500)
+	_ = requestor.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := requestor.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := FromBytes(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.MessageType() != options.DHCPLEASEACTIVE {
+		t.Fatalf("reply = %s, want DHCPLEASEACTIVE", reply.MessageType())
+	}
+	if string(reply.CircuitID()) != "eth0/1" {
+		t.Fatalf("circuit id = %q, want the lease's", reply.CircuitID())
+	}
+	if leaseTime := reply.GetLeaseTime(); leaseTime == 0 || leaseTime > 3600 {
+		t.Fatalf("lease time = %d", leaseTime)
+	}
+}
+
+type multiLeaseStore struct{}
+
+func (multiLeaseStore) Manages(ip net.IP) bool             { return false }
+func (multiLeaseStore) LeaseByIP(ip net.IP) (Lease, bool)  { return Lease{}, false }
+func (multiLeaseStore) LeasesByClientID(id []byte) []Lease { return nil }
+func (multiLeaseStore) LeasesByHardwareAddr(addr net.HardwareAddr) []Lease {
+	now := time.Now()
+	return []Lease{
+		{IP: net.ParseIP("192.0.2.30"), HardwareAddr: addr, LastTransaction: now.Add(-time.Hour)},
+		{IP: net.ParseIP("192.0.2.31"), HardwareAddr: addr, LastTransaction: now.Add(-time.Minute)},
+	}
+}
+
+func TestQuarantineHandler(t *testing.T) {
+	_, production, _ := net.ParseCIDR("192.0.2.0/24")
+	_, quarantine, _ := net.ParseCIDR("198.51.100.0/24")
+	productionPool, err := NewIPPool(production, net.ParseIP("192.0.2.10"), net.ParseIP("192.0.2.20"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quarantinePool, err := NewIPPool(quarantine, net.ParseIP("198.51.100.10"), net.ParseIP("198.51.100.20"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewQuarantineHandler(productionPool, quarantinePool, "https://portal.example.net/api")
+	h.LeaseTime = 86400
+	mux := NewDefaultServerMux(h)
+	const mac = "00:11:22:33:44:55"
+
+	serve := func(req *Message) *Message {
+		t.Helper()
+		req.SetMacAddress(mac)
+		rw := &recordingWriter{request: req}
+		mux.ServeDHCP(req, rw)
+		if len(rw.responses) != 1 {
+			t.Fatalf("%s: responses = %d, want 1", req.MessageType(), len(rw.responses))
+		}
+		return rw.responses[0]
+	}
+
+	offer := serve(NewDiscoverMessage())
+	if !quarantinePool.Contains(offer.YourIPAddr) || offer.GetLeaseTime() != DefaultQuarantineLeaseTime ||
+		offer.CaptivePortal() != "https://portal.example.net/api" {
+		t.Fatalf("quarantine offer = %s", offer)
+	}
+	request := NewRequestMessage()
+	request.SetOption(options.NewRequestedIPAddressOption(offer.YourIPAddr.String()))
+	if ack := serve(request); ack.MessageType() != options.DHCPACK || !ack.YourIPAddr.Equal(offer.YourIPAddr) {
+		t.Fatalf("quarantine ack = %s", ack)
+	}
+	if ack := serve(NewRenewMessage(offer.YourIPAddr.String())); ack.MessageType() != options.DHCPACK {
+		t.Fatalf("unregistered renew = %s", ack)
+	}
+
+	if err := h.Register("00-11-22-33-44-55"); err != nil {
+		t.Fatal(err)
+	}
+	if nak := serve(NewRenewMessage(offer.YourIPAddr.String())); nak.MessageType() != options.DHCPNAK {
+		t.Fatalf("registered renew = %s", nak)
+	}
+	if quarantinePool.IsLeased(offer.YourIPAddr.String()) {
+		t.Fatal("quarantine lease kept after registration")
+	}
+	offer = serve(NewDiscoverMessage())
+	if !productionPool.Contains(offer.YourIPAddr) || offer.GetLeaseTime() != 86400 || offer.CaptivePortal() != "" {
+		t.Fatalf("production offer = %s", offer)
+	}
+}
@@ -88,6 +88,11 @@ func (m *MyServer) HandleDiscover(request *dhcp4.Message, rw dhcp4.OfferWriter)
 		log.Printf("No IP available for %s: %v", mac, err)
 		return
 	}
+	if rc, ok := rw.(dhcp4.RapidCommitWriter); ok && rc.RapidCommit() {
+		log.Printf("Rapid commit of IP %s to %s", ip, mac)
+		rc.SendRapidCommitAck(ip.String(), m.config.DefaultResponseOptions()...)
+		return
+	}
 	log.Printf("Offering IP %s to %s", ip, mac)
 	rw.SendOffer(ip.String(), m.config.DefaultResponseOptions()...)
 }
@@ -160,6 +165,8 @@ func RunServer() {
 	my := NewMyServer(config)
 	log.Printf("IP pool: %s - %s", config.PoolStart, config.PoolEnd)
 	h := dhcp4.NewDefaultServerMux(my)
+	h.RapidCommit = true
+	h.Leases = my.pool
 
 	// Start server
 	addr := fmt.Sprintf(":%d", config.ServerPort)